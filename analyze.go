@@ -0,0 +1,152 @@
+package enumerable
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Explain returns the pending operations of the Enumerable[T] as text, one operation per line from the source
+// Encode the Plan with encoding/json for a machine readable form
+func (e Enumerable[T]) Explain() string {
+	var b strings.Builder
+	for i, n := range e.Plan() {
+		fmt.Fprintf(&b, "%d %v\n", i, n)
+	}
+	return b.String()
+}
+
+// Stats are the statistics of one operation measured by Analyze
+type Stats struct {
+	Node
+	// In is the number of values the operation pulled from the previous one
+	In int `json:"in"`
+	// Out is the number of values pulled from the operation
+	Out int `json:"out"`
+	// Duration is the wall time spent in the operation, excluding the operations it pulled values from
+	Duration time.Duration `json:"duration"`
+	// Allocs is the number of heap allocations made by the operation
+	Allocs uint64 `json:"allocs"`
+	// Bytes is the number of bytes allocated on the heap by the operation
+	Bytes uint64 `json:"bytes"`
+}
+
+// Analysis are the statistics of an evaluation measured by Analyze
+// Analyses encode to JSON with encoding/json
+type Analysis struct {
+	// Stages are the statistics of each operation from the source
+	Stages []Stats `json:"stages"`
+	// Duration is the wall time of the whole evaluation, including collecting the values
+	Duration time.Duration `json:"duration"`
+}
+
+// String formats the Analysis as a table with a row for each operation
+func (a Analysis) String() string {
+	var b strings.Builder
+	t := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(t, "STAGE\tOPERATION\tIN\tOUT\tTIME\tALLOCS\tBYTES")
+	for i, s := range a.Stages {
+		fmt.Fprintf(t, "%d\t%v\t%d\t%d\t%v\t%d\t%d\n", i, s.Node, s.In, s.Out, s.Duration, s.Allocs, s.Bytes)
+	}
+	t.Flush()
+	fmt.Fprintf(&b, "total %v\n", a.Duration)
+	return b.String()
+}
+
+// Analyze evaluates the Enumerable[T] like ToList and returns the values with the statistics of each operation
+// Time and allocations are charged to the operation that was running, not to the operations it pulled values from
+// The allocations are read from the runtime each time a value passes between operations, which stops the world,
+// so Analyze is much slower than ToList and is meant for finding the costly operations of a pipeline
+// Panics if a source fails, use AnalyzeErr for sources that can fail
+func (e Enumerable[T]) Analyze() ([]T, Analysis) {
+	plan := e.Plan()
+	p := &profiler{stats: make([]Stats, len(plan))}
+	for i, n := range plan {
+		p.stats[i].Node = n
+	}
+	start := time.Now()
+	p.start()
+	stack := e.stack
+	e.stack = nil
+	e = profiled(p, 0, e)
+	for i, s := range stack {
+		p.enter(i + 1)
+		e = profiled(p, i+1, s.apply(e))
+		p.exit()
+	}
+	values := e.collect().values
+	for i := 1; i < len(p.stats); i++ {
+		p.stats[i].In = p.stats[i-1].Out
+	}
+	return values, Analysis{Stages: p.stats, Duration: time.Since(start)}
+}
+
+// AnalyzeErr is Analyze for sources that can fail
+// Returns the error of a source that failed, such as an external sort
+func (e Enumerable[T]) AnalyzeErr() (values []T, analysis Analysis, err error) {
+	defer recoverErr(&err)
+	values, analysis = e.Analyze()
+	return values, analysis, nil
+}
+
+// profiled counts the values pulled from the Enumerable[T] and charges the time spent producing them to stage i
+func profiled[T any](p *profiler, i int, e Enumerable[T]) Enumerable[T] {
+	return fromSource(func(s *scope) iterator[T] {
+		p.enter(i)
+		next := e.iterate(s)
+		p.exit()
+		return func() (T, bool) {
+			p.enter(i)
+			v, ok := next()
+			p.exit()
+			if ok {
+				p.stats[i].Out++
+			}
+			return v, ok
+		}
+	})
+}
+
+// profiler charges time and allocations to the stage that is running
+// Stages call each other while the pipeline runs, so the running stage is the top of a stack
+type profiler struct {
+	stats   []Stats
+	running []int
+	last    time.Time
+	mallocs uint64
+	bytes   uint64
+	memory  runtime.MemStats
+}
+
+func (p *profiler) start() {
+	runtime.ReadMemStats(&p.memory)
+	p.mallocs, p.bytes = p.memory.Mallocs, p.memory.TotalAlloc
+	p.last = time.Now()
+}
+
+// charge adds the time and allocations since the last call to the running stage
+// The time spent reading the allocations is not charged to any stage
+func (p *profiler) charge() {
+	now := time.Now()
+	runtime.ReadMemStats(&p.memory)
+	if len(p.running) > 0 {
+		s := &p.stats[p.running[len(p.running)-1]]
+		s.Duration += now.Sub(p.last)
+		s.Allocs += p.memory.Mallocs - p.mallocs
+		s.Bytes += p.memory.TotalAlloc - p.bytes
+	}
+	p.mallocs, p.bytes = p.memory.Mallocs, p.memory.TotalAlloc
+	p.last = time.Now()
+}
+
+func (p *profiler) enter(i int) {
+	p.charge()
+	p.running = append(p.running, i)
+}
+
+func (p *profiler) exit() {
+	p.charge()
+	p.running = p.running[:len(p.running)-1]
+}
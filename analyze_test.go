@@ -0,0 +1,125 @@
+package enumerable
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	e := New([]int{1, 2, 3, 4}).
+		Map(func(i int) int { return i * 2 }).
+		Map(func(i int) int { return i + 1 }).
+		Filter(func(i int) bool { return i > 3 }).
+		Take(2)
+	result := e.Optimize().Explain()
+	expected := "0 Values(4)\n1 Map*2\n2 Filter\n3 Take(2)\n"
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestPlanJSON(t *testing.T) {
+	e := New([]int{1, 2, 3}).Skip(1).Skip(1).Optimize().TakeWhile(func(i int) bool { return i > 0 })
+	result, err := json.Marshal(e.Plan())
+	expected := `[{"op":"Values","n":3},{"op":"Skip","n":2,"fused":2},{"op":"TakeWhile"}]`
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(result) != expected {
+		t.Errorf("Expected %v, got %v", expected, string(result))
+	}
+}
+
+func TestAnalyzeCounts(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}).
+		Filter(func(i int) bool { return i%2 == 0 }).
+		Map(func(i int) int { return i * 10 }).
+		Reverse().
+		Take(3)
+	values, analysis := e.Analyze()
+
+	if expected := []int{100, 80, 60}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	counts := [][2]int{}
+	for _, s := range analysis.Stages {
+		counts = append(counts, [2]int{s.In, s.Out})
+	}
+	expected := [][2]int{{0, 10}, {10, 5}, {5, 5}, {5, 3}, {3, 3}}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, got %v", expected, counts)
+	}
+	if result := analysis.Stages[2].Op; result != OpMap {
+		t.Errorf("Expected %v, got %v", OpMap, result)
+	}
+}
+
+func TestAnalyzeStopsEarly(t *testing.T) {
+	_, analysis := New([]int{1, 2, 3, 4, 5}).Map(func(i int) int { return i }).Take(2).Analyze()
+	result := []int{analysis.Stages[0].Out, analysis.Stages[1].Out, analysis.Stages[2].Out}
+	expected := []int{2, 2, 2}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestAnalyzeCharges(t *testing.T) {
+	var sink []string
+	_, analysis := New([]int{1, 2, 3, 4, 5}).
+		Map(func(i int) int {
+			time.Sleep(2 * time.Millisecond)
+			return i
+		}).
+		Filter(func(i int) bool {
+			sink = append(sink, strings.Repeat(strconv.Itoa(i), 100))
+			return true
+		}).
+		Analyze()
+	sleep, allocate := analysis.Stages[1], analysis.Stages[2]
+
+	if sleep.Duration < 10*time.Millisecond {
+		t.Errorf("Expected at least %v, got %v", 10*time.Millisecond, sleep.Duration)
+	}
+	if allocate.Duration >= sleep.Duration {
+		t.Errorf("Expected less than %v, got %v", sleep.Duration, allocate.Duration)
+	}
+	if allocate.Allocs < 5 || allocate.Bytes < 500 {
+		t.Errorf("Expected at least 5 allocations of 500 bytes, got %v of %v", allocate.Allocs, allocate.Bytes)
+	}
+	if analysis.Duration < sleep.Duration {
+		t.Errorf("Expected at least %v, got %v", sleep.Duration, analysis.Duration)
+	}
+}
+
+func TestAnalysisString(t *testing.T) {
+	_, analysis := New([]int{3, 1, 2}).OrderBy(func(a, b int) int { return a - b }).Analyze()
+	lines := strings.Split(analysis.String(), "\n")
+
+	if !strings.HasPrefix(lines[0], "STAGE") || !strings.Contains(lines[0], "ALLOCS") {
+		t.Errorf("Expected a header, got %v", lines[0])
+	}
+	if fields := strings.Fields(lines[2]); fields[1] != "OrderBy" || fields[2] != "3" || fields[3] != "3" {
+		t.Errorf("Expected OrderBy with 3 values in and out, got %v", lines[2])
+	}
+	if !strings.HasPrefix(lines[3], "total ") {
+		t.Errorf("Expected the total, got %v", lines[3])
+	}
+}
+
+func TestAnalyzeErr(t *testing.T) {
+	failed := errors.New("failed")
+	e := TransformErr(New([]int{1, 2}), func(i int) (int, error) { return 0, failed }).Map(func(i int) int { return i })
+	_, _, err := e.AnalyzeErr()
+
+	if !errors.Is(err, failed) {
+		t.Errorf("Expected %v, got %v", failed, err)
+	}
+}
@@ -0,0 +1,118 @@
+package enumerable
+
+// Chunk splits the Enumerable[T] into slices of at most n values
+// The last chunk holds the remaining values and may be shorter than n
+// Panics if n is less than 1
+// Evaluates lazily, call apply to evaluate
+func Chunk[T any](e Enumerable[T], n int) Enumerable[[]T] {
+	if n < 1 {
+		panic("enumerable: chunk size must be positive")
+	}
+	return fromSource(func(s *scope) iterator[[]T] {
+		next := e.run().iterate(s)
+		return func() ([]T, bool) {
+			chunk := make([]T, 0, n)
+			for len(chunk) < n {
+				v, ok := next()
+				if !ok {
+					break
+				}
+				chunk = append(chunk, v)
+			}
+			return chunk, len(chunk) > 0
+		}
+	})
+}
+
+// Window returns windows of size values, starting a new window every step values
+// Windows overlap when step is less than size, tumble when step equals size and
+// leave gaps when step is greater than size
+// Only full windows are returned, trailing values that do not fill a window are dropped
+// Panics if size or step is less than 1
+// Evaluates lazily, call apply to evaluate
+func Window[T any](e Enumerable[T], size, step int) Enumerable[[]T] {
+	if size < 1 || step < 1 {
+		panic("enumerable: window size and step must be positive")
+	}
+	return fromSource(func(s *scope) iterator[[]T] {
+		next := e.run().iterate(s)
+		window := make([]T, 0, size)
+		skip := 0
+		return func() ([]T, bool) {
+			for len(window) < size {
+				v, ok := next()
+				if !ok {
+					return nil, false
+				}
+				if skip > 0 {
+					skip--
+					continue
+				}
+				window = append(window, v)
+			}
+			result := make([]T, size)
+			copy(result, window)
+			if step < size {
+				window = append(window[:0], window[step:]...)
+			} else {
+				window = window[:0]
+				skip = step - size
+			}
+			return result, true
+		}
+	})
+}
+
+// Pairwise returns each value of the Enumerable[T] paired with the value that follows it
+// Returns an empty Enumerable when there are fewer than two values
+// Evaluates lazily, call apply to evaluate
+func Pairwise[T any](e Enumerable[T]) Enumerable[[2]T] {
+	return fromSource(func(s *scope) iterator[[2]T] {
+		next := e.run().iterate(s)
+		previous, started := next()
+		return func() ([2]T, bool) {
+			if !started {
+				return [2]T{}, false
+			}
+			v, ok := next()
+			if !ok {
+				started = false
+				return [2]T{}, false
+			}
+			pair := [2]T{previous, v}
+			previous = v
+			return pair, true
+		}
+	})
+}
+
+// ChunkBy splits the Enumerable[T] into runs of consecutive values with equal keys
+// Evaluates lazily, call apply to evaluate
+func ChunkBy[T any, K comparable](e Enumerable[T], key func(T) K) Enumerable[[]T] {
+	return fromSource(func(s *scope) iterator[[]T] {
+		next := e.run().iterate(s)
+		pending, ok := next()
+		var pendingKey K
+		if ok {
+			pendingKey = key(pending)
+		}
+		return func() ([]T, bool) {
+			if !ok {
+				return nil, false
+			}
+			k := pendingKey
+			chunk := []T{pending}
+			for {
+				pending, ok = next()
+				if !ok {
+					return chunk, true
+				}
+				pendingKey = key(pending)
+				if pendingKey != k {
+					return chunk, true
+				}
+				chunk = append(chunk, pending)
+			}
+		}
+	})
+}
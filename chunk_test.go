@@ -0,0 +1,144 @@
+package enumerable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5})
+	result := Chunk(e, 2).Apply()
+	expected := [][]int{{1, 2}, {3, 4}, {5}}
+
+	if !reflect.DeepEqual(result.values, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.values)
+	}
+}
+
+func TestChunkEmpty(t *testing.T) {
+	e := New([]int{})
+	result := Chunk(e, 2).Apply()
+
+	if len(result.values) != 0 {
+		t.Errorf("Expected 0 values, got %d", len(result.values))
+	}
+}
+
+func TestChunkAfterFilter(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5, 6})
+	result := Chunk(e.Filter(func(i int) bool { return i%2 == 0 }), 2).Apply()
+	expected := [][]int{{2, 4}, {6}}
+
+	if !reflect.DeepEqual(result.values, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.values)
+	}
+}
+
+func TestChunkStreams(t *testing.T) {
+	pulled := 0
+	e := fromSource(func(*scope) iterator[int] {
+		i := 0
+		return func() (int, bool) {
+			pulled++
+			i++
+			return i, true
+		}
+	})
+	next := Chunk(e, 3).run().iterate(newScope())
+	chunk, _ := next()
+	expected := []int{1, 2, 3}
+
+	if !reflect.DeepEqual(chunk, expected) {
+		t.Errorf("Expected %v, got %v", expected, chunk)
+	}
+	if pulled != 3 {
+		t.Errorf("Expected 3 values pulled, got %d", pulled)
+	}
+}
+
+func TestChunkPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic")
+		}
+	}()
+	Chunk(New([]int{1}), 0)
+}
+
+func TestWindowSliding(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5})
+	result := Window(e, 3, 1).Apply()
+	expected := [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}
+
+	if !reflect.DeepEqual(result.values, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.values)
+	}
+}
+
+func TestWindowTumbling(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5})
+	result := Window(e, 2, 2).Apply()
+	expected := [][]int{{1, 2}, {3, 4}}
+
+	if !reflect.DeepEqual(result.values, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.values)
+	}
+}
+
+func TestWindowGaps(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5, 6, 7})
+	result := Window(e, 2, 3).Apply()
+	expected := [][]int{{1, 2}, {4, 5}}
+
+	if !reflect.DeepEqual(result.values, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.values)
+	}
+}
+
+func TestWindowTooShort(t *testing.T) {
+	e := New([]int{1, 2})
+	result := Window(e, 3, 1).Apply()
+
+	if len(result.values) != 0 {
+		t.Errorf("Expected 0 values, got %d", len(result.values))
+	}
+}
+
+func TestPairwise(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result := Pairwise(e).Apply()
+	expected := [][2]int{{1, 2}, {2, 3}}
+
+	if !reflect.DeepEqual(result.values, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.values)
+	}
+}
+
+func TestPairwiseSingle(t *testing.T) {
+	e := New([]int{1})
+	result := Pairwise(e).Apply()
+
+	if len(result.values) != 0 {
+		t.Errorf("Expected 0 values, got %d", len(result.values))
+	}
+}
+
+func TestChunkBy(t *testing.T) {
+	e := New([]int{1, 3, 2, 4, 6, 5})
+	result := ChunkBy(e, func(i int) bool { return i%2 == 0 }).Apply()
+	expected := [][]int{{1, 3}, {2, 4, 6}, {5}}
+
+	if !reflect.DeepEqual(result.values, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.values)
+	}
+}
+
+func TestChunkByReevaluates(t *testing.T) {
+	e := ChunkBy(New([]string{"a", "a", "b"}), func(s string) string { return s })
+	first := e.ToList()
+	second := e.ToList()
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected %v, got %v", first, second)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// reader reads values from files or standard input
+type reader struct {
+	files  []string
+	stdin  io.Reader
+	format string
+	// columns is the header of the first CSV input, used to order the columns of the output
+	columns []string
+}
+
+// values returns a sequence of the values of every input in turn
+func (r *reader) values() iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		if len(r.files) == 0 {
+			r.read("stdin", r.stdin, r.format, yield)
+			return
+		}
+		for _, name := range r.files {
+			f, err := os.Open(name)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			format := r.format
+			if format == "" && strings.EqualFold(filepath.Ext(name), ".csv") {
+				format = "csv"
+			}
+			more := r.read(name, f, format, yield)
+			f.Close()
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// read yields the values of in, returning false if yield stopped or there was an error
+func (r *reader) read(name string, in io.Reader, format string, yield func(any, error) bool) bool {
+	if format == "csv" {
+		return r.readCSV(name, in, yield)
+	}
+	return readJSON(name, in, yield)
+}
+
+func readJSON(name string, in io.Reader, yield func(any, error) bool) bool {
+	d := json.NewDecoder(in)
+	d.UseNumber()
+	for {
+		var v any
+		if err := d.Decode(&v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return true
+			}
+			yield(nil, fmt.Errorf("%s: %w", name, err))
+			return false
+		}
+		v, err := normalize(v)
+		if err != nil {
+			yield(nil, fmt.Errorf("%s: %w", name, err))
+			return false
+		}
+		if !yield(v, nil) {
+			return false
+		}
+	}
+}
+
+// normalize converts the json.Number values in v to int64 if they are integers and float64 otherwise
+func normalize(v any) (any, error) {
+	var err error
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case []any:
+		for i := range v {
+			if v[i], err = normalize(v[i]); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		for k := range v {
+			if v[k], err = normalize(v[k]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+func (r *reader) readCSV(name string, in io.Reader, yield func(any, error) bool) bool {
+	c := csv.NewReader(in)
+	header, err := c.Read()
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+	if err != nil {
+		yield(nil, fmt.Errorf("%s: %w", name, err))
+		return false
+	}
+	if r.columns == nil {
+		r.columns = header
+	}
+	for {
+		record, err := c.Read()
+		if errors.Is(err, io.EOF) {
+			return true
+		}
+		if err != nil {
+			yield(nil, fmt.Errorf("%s: %w", name, err))
+			return false
+		}
+		v := make(map[string]any, len(header))
+		for i, field := range record {
+			v[header[i]] = parseField(field)
+		}
+		if !yield(v, nil) {
+			return false
+		}
+	}
+}
+
+// parseField converts a CSV field to null if empty, or to a number or boolean if it is one
+// Numbers with leading zeros such as zip codes and identifiers, and numbers that are not finite, are kept as strings
+func parseField(field string) any {
+	switch {
+	case field == "":
+		return nil
+	case field == "true" || field == "false":
+		return field == "true"
+	case leadingZero(field):
+	case strings.ContainsAny(field[:1], "+-.0123456789"):
+		if i, err := strconv.ParseInt(field, 10, 64); err == nil {
+			return i
+		}
+		// ParseFloat also accepts Inf, Infinity and NaN, which are kept as strings since JSON has no such numbers
+		if f, err := strconv.ParseFloat(field, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f
+		}
+	}
+	return field
+}
+
+// leadingZero returns true if the digits of field, after an optional sign, start with a zero followed by another digit
+func leadingZero(field string) bool {
+	digits := strings.TrimLeft(field, "+-")
+	return len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9'
+}
@@ -0,0 +1,115 @@
+// Command enumerable streams JSON or CSV values through a pipeline and writes them as JSON lines, CSV or a table
+//
+// Usage:
+//
+//	enumerable [flags] pipeline [file ...]
+//	enumerable [flags] -filter expression ... [file ...]
+//
+// The pipeline uses the syntax of the dsl package, for example
+//
+//	enumerable 'filter .status == "error" | map .latency_ms | take 100 | avg' requests.jsonl
+//
+// or can be given as stage flags, which are applied in the order they appear
+//
+//	enumerable -filter '.status == "error"' -sort '.latency_ms desc' -take 10 -out table requests.csv
+//
+// Values are read from the files, or from standard input when there are none. JSON input is a sequence of
+// JSON values such as JSON lines. CSV input has a header row and each record becomes an object keyed by
+// the header, with empty fields read as null and numbers and booleans converted from text
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	enumerable "github.com/sdehm/go-enumerable"
+	"github.com/sdehm/go-enumerable/dsl"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns its exit code, 2 for usage errors and 1 for other errors
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("enumerable", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	in := flags.String("in", "", "input `format`, json or csv, by default csv for .csv files and json otherwise")
+	out := flags.String("out", "json", "output `format`, json, csv or table")
+	var stages []string
+	stage := func(name string) func(string) error {
+		return func(arg string) error {
+			stages = append(stages, strings.TrimSpace(name+" "+arg))
+			return nil
+		}
+	}
+	flags.Func("filter", "keep the values matching `expression`", stage("filter"))
+	flags.Func("map", "replace the values by `expression`", stage("map"))
+	flags.Func("group", "group the values by `key`, optionally followed by an aggregate such as count or sum .field", stage("group"))
+	flags.Func("sort", "sort the values by `key`, optionally followed by desc", stage("sort"))
+	flags.Func("take", "take the first `n` values", stage("take"))
+	flags.Func("skip", "skip the first `n` values", stage("skip"))
+	flags.BoolFunc("reverse", "reverse the values", func(string) error { return stage("reverse")("") })
+	flags.Func("aggregate", "`aggregate` of the values, count, sum, avg, min or max", stage(""))
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: enumerable [flags] pipeline [file ...]\n       enumerable [flags] -filter expression ... [file ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	files := flags.Args()
+	src := strings.Join(stages, " | ")
+	if src == "" {
+		if len(files) == 0 {
+			fmt.Fprintln(stderr, "enumerable: missing pipeline")
+			flags.Usage()
+			return 2
+		}
+		src, files = files[0], files[1:]
+	}
+	if *in != "" && *in != "json" && *in != "csv" {
+		fmt.Fprintf(stderr, "enumerable: unknown input format %q\n", *in)
+		return 2
+	}
+	w, ok := writers[*out]
+	if !ok {
+		fmt.Fprintf(stderr, "enumerable: unknown output format %q\n", *out)
+		return 2
+	}
+	pipeline, err := dsl.Compile[any](src)
+	if err != nil {
+		fmt.Fprintf(stderr, "enumerable: %v\n", err)
+		return 1
+	}
+	r := &reader{files: files, stdin: stdin, format: *in}
+	if err := write(w, stdout, pipeline, enumerable.FromSeqErr(r.values()), r); err != nil {
+		fmt.Fprintf(stderr, "enumerable: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// write evaluates the pipeline over values and writes the result, streaming it if the writer supports it
+func write(w writer, out io.Writer, p *dsl.Pipeline[any], values enumerable.Enumerable[any], r *reader) error {
+	stages := p.Stages()
+	if !stages[len(stages)-1].IsAggregate() && w.stream != nil {
+		return w.stream(out, p.Apply(values))
+	}
+	result, err := p.Run(values)
+	if err != nil {
+		return err
+	}
+	list, ok := result.([]any)
+	if !ok {
+		list = []any{result}
+	}
+	return w.all(out, list, r.columns)
+}
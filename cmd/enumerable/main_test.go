@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+	}{
+		{"dsl_avg", []string{`filter .status == "error" | map .latency_ms | take 100 | avg`, "testdata/requests.jsonl"}, ""},
+		{"dsl_values", []string{`filter .latency_ms > 20 | map .path`, "testdata/requests.jsonl"}, ""},
+		{"flags_table", []string{"-out", "table", "-filter", `.status == "error"`, "-sort", ".latency_ms desc", "-take", "2", "testdata/requests.csv"}, ""},
+		{"flags_order", []string{"-take", "2", "-reverse", "-map", ".path", "testdata/requests.jsonl"}, ""},
+		{"flags_aggregate", []string{"-map", ".latency_ms", "-aggregate", "max", "testdata/requests.csv"}, ""},
+		{"csv_to_csv", []string{"-out", "csv", `filter .cached == false`, "testdata/requests.csv"}, ""},
+		{"json_to_csv", []string{"-out", "csv", `filter .tags != null`, "testdata/requests.jsonl"}, ""},
+		{"group_table", []string{"-out", "table", "group .status avg .latency_ms", "testdata/requests.jsonl"}, ""},
+		{"group_values", []string{"-group", ".path", "-map", ".values", "testdata/requests.csv"}, ""},
+		{"count_table", []string{"-out", "table", "count", "testdata/requests.jsonl", "testdata/requests.csv"}, ""},
+		{"stdin_json", []string{"sort . desc"}, "3 1 \"two\" null\n[4]"},
+		{"stdin_csv", []string{"-in", "csv", "map .b"}, "a,b\n1,x y\n2,\n"},
+		{"syntax_error", []string{"filter .status = 1", "testdata/requests.jsonl"}, ""},
+		{"type_error", []string{"map .path | sum", "testdata/requests.jsonl"}, ""},
+		{"malformed_input", []string{"count", "testdata/malformed.jsonl"}, ""},
+		{"missing_file", []string{"count", "testdata/missing.jsonl"}, ""},
+		{"missing_pipeline", []string{"-out", "table"}, ""},
+		{"unknown_format", []string{"-out", "xml", "count"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			actual := fmt.Sprintf("exit %d\n-- stdout --\n%s-- stderr --\n%s", code, stdout.String(), stderr.String())
+			golden(t, filepath.Join("testdata", tt.name+".golden"), actual)
+		})
+	}
+}
+
+func golden(t *testing.T, path, actual string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != string(expected) {
+		t.Errorf("Expected %s to be\n%s\ngot\n%s", path, expected, actual)
+	}
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		field    string
+		expected any
+	}{
+		{"", nil},
+		{"true", true},
+		{"0", int64(0)},
+		{"-0", int64(0)},
+		{"0.5", 0.5},
+		{"-12", int64(-12)},
+		{"1e3", 1000.0},
+		{"01234", "01234"},
+		{"-007", "-007"},
+		{"00.5", "00.5"},
+		{"12ab", "12ab"},
+		{"Inf", "Inf"},
+		{"+Inf", "+Inf"},
+		{"-inf", "-inf"},
+		{"-Infinity", "-Infinity"},
+		{"NaN", "NaN"},
+		{"+nan", "+nan"},
+		{"1e999", "1e999"},
+	}
+	for _, tt := range tests {
+		actual := parseField(tt.field)
+		if actual != tt.expected {
+			t.Errorf("Expected %q to be %#v, got %#v", tt.field, tt.expected, actual)
+		}
+	}
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestStreamJSONStopsAtWriteError(t *testing.T) {
+	pulled := 0
+	values := enumerable.Transform(enumerable.New([]int{1, 2, 3, 4, 5}), func(v int) any { return v })
+	counted := enumerable.TransformErr(values, func(v any) (any, error) {
+		pulled++
+		return v, nil
+	})
+	w := &failingWriter{}
+	err := streamJSON(w, counted)
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("Expected disk full, got %v", err)
+	}
+	if w.writes != 1 {
+		t.Errorf("Expected 1 write, got %d", w.writes)
+	}
+	if pulled != 1 {
+		t.Errorf("Expected 1 value pulled, got %d", pulled)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+// writer writes values in an output format
+type writer struct {
+	// stream writes values as they are evaluated, nil if the format needs every value first
+	stream func(io.Writer, enumerable.Enumerable[any]) error
+	// all writes a list of values, columns is the preferred order of the fields of objects
+	all func(w io.Writer, values []any, columns []string) error
+}
+
+var writers = map[string]writer{
+	"json":  {streamJSON, writeJSON},
+	"csv":   {nil, writeCSV},
+	"table": {nil, writeTable},
+}
+
+// streamJSON writes values as they are evaluated, stopping the evaluation at the first error writing a value
+func streamJSON(w io.Writer, values enumerable.Enumerable[any]) error {
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	written := enumerable.TransformErr(values, func(v any) (struct{}, error) {
+		return struct{}{}, e.Encode(v)
+	})
+	return written.ForEachErr(func(struct{}) {})
+}
+
+func writeJSON(w io.Writer, values []any, _ []string) error {
+	return streamJSON(w, enumerable.New(values))
+}
+
+func writeCSV(w io.Writer, values []any, columns []string) error {
+	c := csv.NewWriter(w)
+	for _, row := range rows(values, columns) {
+		if err := c.Write(row); err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+func writeTable(w io.Writer, values []any, columns []string) error {
+	t := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	replacer := strings.NewReplacer("\t", " ", "\n", " ")
+	for _, row := range rows(values, columns) {
+		for i := range row {
+			row[i] = replacer.Replace(row[i])
+		}
+		if _, err := io.WriteString(t, strings.Join(row, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	return t.Flush()
+}
+
+// rows returns a header and a row per value, objects have a column per field and other values a value column
+// Fields are ordered by columns first then by name
+func rows(values []any, columns []string) [][]string {
+	seen := map[string]bool{}
+	var names []string
+	for _, v := range values {
+		for name := range fields(v) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	var header []string
+	for _, c := range columns {
+		if seen[c] {
+			header = append(header, c)
+			delete(seen, c)
+		}
+	}
+	for _, name := range names {
+		if seen[name] {
+			header = append(header, name)
+		}
+	}
+	result := [][]string{header}
+	for _, v := range values {
+		f := fields(v)
+		row := make([]string, len(header))
+		for i, name := range header {
+			row[i] = formatCell(f[name])
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+func fields(v any) map[string]any {
+	if m, ok := v.(map[string]any); ok {
+		return m
+	}
+	return map[string]any{"value": v}
+}
+
+// formatCell formats a value for CSV and table output, strings as they are and other values as JSON
+func formatCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,84 @@
+package enumerable
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// Codec writes values to and reads values from the temporary files used by disk backed operations
+type Codec[T any] interface {
+	NewEncoder(w io.Writer) Encoder[T]
+	NewDecoder(r io.Reader) Decoder[T]
+}
+
+// Encoder writes values to a stream
+type Encoder[T any] interface {
+	Encode(v T) error
+}
+
+// Decoder reads values from a stream written by the matching Encoder
+// Returns io.EOF once there are no more values
+type Decoder[T any] interface {
+	Decode() (T, error)
+}
+
+// GobCodec encodes values with encoding/gob
+// Only exported fields of structs are written
+type GobCodec[T any] struct{}
+
+func (GobCodec[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return gobEncoder[T]{gob.NewEncoder(w)}
+}
+
+func (GobCodec[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return gobDecoder[T]{gob.NewDecoder(r)}
+}
+
+type gobEncoder[T any] struct {
+	encoder *gob.Encoder
+}
+
+func (e gobEncoder[T]) Encode(v T) error {
+	return e.encoder.Encode(v)
+}
+
+type gobDecoder[T any] struct {
+	decoder *gob.Decoder
+}
+
+func (d gobDecoder[T]) Decode() (T, error) {
+	var v T
+	err := d.decoder.Decode(&v)
+	return v, err
+}
+
+// JSONCodec encodes values as JSON lines with encoding/json
+// Only exported fields of structs are written
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return jsonEncoder[T]{json.NewEncoder(w)}
+}
+
+func (JSONCodec[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return jsonDecoder[T]{json.NewDecoder(r)}
+}
+
+type jsonEncoder[T any] struct {
+	encoder *json.Encoder
+}
+
+func (e jsonEncoder[T]) Encode(v T) error {
+	return e.encoder.Encode(v)
+}
+
+type jsonDecoder[T any] struct {
+	decoder *json.Decoder
+}
+
+func (d jsonDecoder[T]) Decode() (T, error) {
+	var v T
+	err := d.decoder.Decode(&v)
+	return v, err
+}
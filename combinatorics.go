@@ -0,0 +1,205 @@
+package enumerable
+
+// Permutations returns every ordered arrangement of k values of the Enumerable[T]
+// Arrangements are produced in lexicographic order of the positions of the values
+// Returns an empty Enumerable if k is negative or greater than the number of values
+// Evaluates lazily and produces each arrangement on demand, call apply to evaluate
+func Permutations[T any](e Enumerable[T], k int) Enumerable[[]T] {
+	return fromSource(func(s *scope) iterator[[]T] {
+		pool := e.ToList()
+		n := len(pool)
+		if k < 0 || k > n {
+			return emptyIterator[[]T]
+		}
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		cycles := make([]int, k)
+		for i := range cycles {
+			cycles[i] = n - i
+		}
+		first := true
+		done := false
+		return func() ([]T, bool) {
+			if done {
+				return nil, false
+			}
+			if first {
+				first = false
+				return pick(pool, indices[:k]), true
+			}
+			for i := k - 1; i >= 0; i-- {
+				cycles[i]--
+				if cycles[i] == 0 {
+					// rotate the index at i to the end and reset its cycle
+					moved := indices[i]
+					copy(indices[i:], indices[i+1:])
+					indices[n-1] = moved
+					cycles[i] = n - i
+					continue
+				}
+				j := n - cycles[i]
+				indices[i], indices[j] = indices[j], indices[i]
+				return pick(pool, indices[:k]), true
+			}
+			done = true
+			return nil, false
+		}
+	})
+}
+
+// Combinations returns every selection of k values of the Enumerable[T] in their original order
+// Returns an empty Enumerable if k is negative or greater than the number of values
+// Evaluates lazily and produces each selection on demand, call apply to evaluate
+func Combinations[T any](e Enumerable[T], k int) Enumerable[[]T] {
+	return fromSource(func(s *scope) iterator[[]T] {
+		return combinations(e.ToList(), k)
+	})
+}
+
+// CombinationsWithReplacement returns every selection of k values of the Enumerable[T] where values may repeat
+// Returns an empty Enumerable if k is negative or the Enumerable[T] is empty and k is positive
+// Evaluates lazily and produces each selection on demand, call apply to evaluate
+func CombinationsWithReplacement[T any](e Enumerable[T], k int) Enumerable[[]T] {
+	return fromSource(func(s *scope) iterator[[]T] {
+		pool := e.ToList()
+		n := len(pool)
+		if k < 0 || (n == 0 && k > 0) {
+			return emptyIterator[[]T]
+		}
+		indices := make([]int, k)
+		first := true
+		done := false
+		return func() ([]T, bool) {
+			if done {
+				return nil, false
+			}
+			if first {
+				first = false
+				return pick(pool, indices), true
+			}
+			i := k - 1
+			for i >= 0 && indices[i] == n-1 {
+				i--
+			}
+			if i < 0 {
+				done = true
+				return nil, false
+			}
+			next := indices[i] + 1
+			for j := i; j < k; j++ {
+				indices[j] = next
+			}
+			return pick(pool, indices), true
+		}
+	})
+}
+
+// CartesianProduct returns every way of choosing one value from each Enumerable
+// The rightmost Enumerable varies fastest
+// Returns a single empty selection when no Enumerables are given and nothing if any of them is empty
+// Evaluates lazily and produces each selection on demand, call apply to evaluate
+func CartesianProduct[T any](es ...Enumerable[T]) Enumerable[[]T] {
+	return fromSource(func(s *scope) iterator[[]T] {
+		pools := make([][]T, len(es))
+		for i, e := range es {
+			pools[i] = e.ToList()
+			if len(pools[i]) == 0 {
+				return emptyIterator[[]T]
+			}
+		}
+		indices := make([]int, len(pools))
+		done := false
+		return func() ([]T, bool) {
+			if done {
+				return nil, false
+			}
+			result := make([]T, len(pools))
+			for i, pool := range pools {
+				result[i] = pool[indices[i]]
+			}
+			// advance the indices like an odometer
+			i := len(indices) - 1
+			for ; i >= 0; i-- {
+				indices[i]++
+				if indices[i] < len(pools[i]) {
+					break
+				}
+				indices[i] = 0
+			}
+			done = i < 0
+			return result, true
+		}
+	})
+}
+
+// PowerSet returns every subset of the values of the Enumerable[T], ordered by size
+// Values within each subset keep their original order
+// Evaluates lazily and produces each subset on demand, call apply to evaluate
+func PowerSet[T any](e Enumerable[T]) Enumerable[[]T] {
+	return fromSource(func(s *scope) iterator[[]T] {
+		pool := e.ToList()
+		k := 0
+		next := combinations(pool, k)
+		return func() ([]T, bool) {
+			for k <= len(pool) {
+				if v, ok := next(); ok {
+					return v, true
+				}
+				k++
+				next = combinations(pool, k)
+			}
+			return nil, false
+		}
+	})
+}
+
+func combinations[T any](pool []T, k int) iterator[[]T] {
+	n := len(pool)
+	if k < 0 || k > n {
+		return emptyIterator[[]T]
+	}
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	first := true
+	done := false
+	return func() ([]T, bool) {
+		if done {
+			return nil, false
+		}
+		if first {
+			first = false
+			return pick(pool, indices), true
+		}
+		i := k - 1
+		for i >= 0 && indices[i] == i+n-k {
+			i--
+		}
+		if i < 0 {
+			done = true
+			return nil, false
+		}
+		indices[i]++
+		for j := i + 1; j < k; j++ {
+			indices[j] = indices[j-1] + 1
+		}
+		return pick(pool, indices), true
+	}
+}
+
+// pick returns a new slice of the values of pool at the given indices
+func pick[T any](pool []T, indices []int) []T {
+	result := make([]T, len(indices))
+	for i, index := range indices {
+		result[i] = pool[index]
+	}
+	return result
+}
+
+func emptyIterator[T any]() (T, bool) {
+	var zero T
+	return zero, false
+}
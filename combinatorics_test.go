@@ -0,0 +1,114 @@
+package enumerable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPermutations(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result := Permutations(e, 2).ToList()
+	expected := [][]int{{1, 2}, {1, 3}, {2, 1}, {2, 3}, {3, 1}, {3, 2}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestPermutationsFull(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result := Permutations(e, 3).ToList()
+	expected := [][]int{{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	e := New([]string{"a", "b", "c", "d"})
+	result := Combinations(e, 2).ToList()
+	expected := [][]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestCombinationsWithReplacement(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result := CombinationsWithReplacement(e, 2).ToList()
+	expected := [][]int{{1, 1}, {1, 2}, {1, 3}, {2, 2}, {2, 3}, {3, 3}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestCartesianProduct(t *testing.T) {
+	result := CartesianProduct(New([]int{1, 2}), New([]int{3}), New([]int{4, 5})).ToList()
+	expected := [][]int{{1, 3, 4}, {1, 3, 5}, {2, 3, 4}, {2, 3, 5}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestCartesianProductEdges(t *testing.T) {
+	if result := CartesianProduct[int]().ToList(); !reflect.DeepEqual(result, [][]int{{}}) {
+		t.Errorf("Expected %v, got %v", [][]int{{}}, result)
+	}
+	if result := CartesianProduct(New([]int{1}), New([]int{})).ToList(); len(result) != 0 {
+		t.Errorf("Expected 0 values, got %d", len(result))
+	}
+}
+
+func TestPowerSet(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result := PowerSet(e).ToList()
+	expected := [][]int{{}, {1}, {2}, {3}, {1, 2}, {1, 3}, {2, 3}, {1, 2, 3}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestCombinatoricsCounts(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5})
+	tests := []struct {
+		name     string
+		result   Enumerable[[]int]
+		expected int
+	}{
+		{"Permutations 5 3", Permutations(e, 3), 60},
+		{"Permutations 5 0", Permutations(e, 0), 1},
+		{"Permutations 5 6", Permutations(e, 6), 0},
+		{"Permutations 5 -1", Permutations(e, -1), 0},
+		{"Combinations 5 3", Combinations(e, 3), 10},
+		{"Combinations 5 0", Combinations(e, 0), 1},
+		{"Combinations 5 6", Combinations(e, 6), 0},
+		{"CombinationsWithReplacement 5 3", CombinationsWithReplacement(e, 3), 35},
+		{"CombinationsWithReplacement 0 1", CombinationsWithReplacement(New([]int{}), 1), 0},
+		{"PowerSet 5", PowerSet(e), 32},
+		{"PowerSet 0", PowerSet(New([]int{})), 1},
+	}
+	for _, test := range tests {
+		if result := test.result.Count(); result != test.expected {
+			t.Errorf("%s: expected %d, got %d", test.name, test.expected, result)
+		}
+	}
+}
+
+func TestCombinatoricsTake(t *testing.T) {
+	values := make([]int, 20)
+	for i := range values {
+		values[i] = i
+	}
+	// 20! permutations could never be materialized
+	result := Permutations(New(values), 20).Take(2).ToList()
+	expected := [][]int{values, append(append([]int{}, values[:18]...), 19, 18)}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
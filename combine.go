@@ -0,0 +1,160 @@
+package enumerable
+
+import "container/heap"
+
+// Concat returns the values of each Enumerable[T] one after another
+// Evaluates lazily, call apply to evaluate
+func Concat[T any](es ...Enumerable[T]) Enumerable[T] {
+	return fromSource(func(s *scope) iterator[T] {
+		i := 0
+		var next iterator[T]
+		return func() (T, bool) {
+			for i < len(es) {
+				if next == nil {
+					next = es[i].run().iterate(s)
+				}
+				if v, ok := next(); ok {
+					return v, true
+				}
+				next = nil
+				i++
+			}
+			var zero T
+			return zero, false
+		}
+	})
+}
+
+// Prepend a value to the Enumerable[T] and return a new Enumerable[T]
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) Prepend(value T) Enumerable[T] {
+	return e.InsertAt(0, value)
+}
+
+// InsertAt inserts values before index i of the Enumerable[T] and returns a new Enumerable[T]
+// If i is greater than the length of the Enumerable[T], the values are appended
+// Panics if i is negative
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) InsertAt(i int, values ...T) Enumerable[T] {
+	if i < 0 {
+		panic("enumerable: insert index must not be negative")
+	}
+	return e.stream("InsertAt", func(next iterator[T]) iterator[T] {
+		index := 0
+		inserted := 0
+		return func() (T, bool) {
+			if index == i && inserted < len(values) {
+				inserted++
+				return values[inserted-1], true
+			}
+			v, ok := next()
+			if ok {
+				index++
+				return v, true
+			}
+			// the Enumerable[T] was shorter than i so append the remaining values
+			if inserted < len(values) {
+				inserted++
+				return values[inserted-1], true
+			}
+			return v, false
+		}
+	})
+}
+
+// Interleave returns the values of each Enumerable[T] in turn, one value from each at a time
+// Enumerables that run out are skipped while the others continue
+// Evaluates lazily, call apply to evaluate
+func Interleave[T any](es ...Enumerable[T]) Enumerable[T] {
+	return fromSource(func(s *scope) iterator[T] {
+		iterators := make([]iterator[T], len(es))
+		for i, e := range es {
+			iterators[i] = e.run().iterate(s)
+		}
+		current := 0
+		return func() (T, bool) {
+			for len(iterators) > 0 {
+				current %= len(iterators)
+				if v, ok := iterators[current](); ok {
+					current++
+					return v, true
+				}
+				iterators = append(iterators[:current], iterators[current+1:]...)
+			}
+			var zero T
+			return zero, false
+		}
+	})
+}
+
+// MergeSorted merges Enumerables that are each already sorted by cmp into a single sorted Enumerable[T]
+// cmp returns a negative number when a sorts before b, zero when equal and a positive number otherwise
+// Equal values are returned in the order of the Enumerables they came from
+// Evaluates lazily, call apply to evaluate
+func MergeSorted[T any](cmp func(a, b T) int, es ...Enumerable[T]) Enumerable[T] {
+	return fromSource(func(s *scope) iterator[T] {
+		iterators := make([]iterator[T], len(es))
+		for i, e := range es {
+			iterators[i] = e.run().iterate(s)
+		}
+		return mergeIterators(cmp, iterators)
+	})
+}
+
+// mergeIterators merges iterators that are each sorted by cmp with a heap of their next values
+// Equal values are returned in the order of the iterators they came from
+func mergeIterators[T any](cmp func(a, b T) int, iterators []iterator[T]) iterator[T] {
+	h := &mergeHeap[T]{cmp: cmp}
+	for i, next := range iterators {
+		if v, ok := next(); ok {
+			h.items = append(h.items, mergeItem[T]{v, i, next})
+		}
+	}
+	heap.Init(h)
+	return func() (T, bool) {
+		if h.Len() == 0 {
+			var zero T
+			return zero, false
+		}
+		top := &h.items[0]
+		v := top.value
+		if next, ok := top.next(); ok {
+			top.value = next
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+		return v, true
+	}
+}
+
+type mergeItem[T any] struct {
+	value  T
+	source int
+	next   iterator[T]
+}
+
+// mergeHeap holds the head of each sorted Enumerable ordered by value then by source
+type mergeHeap[T any] struct {
+	items []mergeItem[T]
+	cmp   func(a, b T) int
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.items) }
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	if c := h.cmp(h.items[i].value, h.items[j].value); c != 0 {
+		return c < 0
+	}
+	return h.items[i].source < h.items[j].source
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap[T]) Push(x any) { h.items = append(h.items, x.(mergeItem[T])) }
+
+func (h *mergeHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
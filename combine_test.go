@@ -0,0 +1,121 @@
+package enumerable
+
+import (
+	"cmp"
+	"reflect"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	a := New([]int{1, 2})
+	b := New([]int{}).Append(3)
+	c := New([]int{4, 5}).Filter(func(i int) bool { return i > 4 })
+	result := Concat(a, b, c).ToList()
+	expected := []int{1, 2, 3, 5}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestConcatEmpty(t *testing.T) {
+	result := Concat[int]().ToList()
+
+	if len(result) != 0 {
+		t.Errorf("Expected 0 values, got %d", len(result))
+	}
+}
+
+func TestConcatIsLazy(t *testing.T) {
+	calls := 0
+	b := New([]int{3, 4}).Map(func(i int) int {
+		calls++
+		return i
+	})
+	result, _ := Concat(New([]int{1, 2}), b).First()
+
+	if result != 1 {
+		t.Errorf("Expected %d, got %d", 1, result)
+	}
+	if calls != 0 {
+		t.Errorf("Expected 0 calls, got %d", calls)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	e := New([]int{1, 2})
+	result := e.Prepend(0).ToList()
+	expected := []int{0, 1, 2}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestInsertAt(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	tests := []struct {
+		index    int
+		expected []int
+	}{
+		{0, []int{8, 9, 1, 2, 3}},
+		{1, []int{1, 8, 9, 2, 3}},
+		{3, []int{1, 2, 3, 8, 9}},
+		{5, []int{1, 2, 3, 8, 9}},
+	}
+	for _, test := range tests {
+		result := e.InsertAt(test.index, 8, 9).ToList()
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("InsertAt %d: expected %v, got %v", test.index, test.expected, result)
+		}
+	}
+}
+
+func TestInsertAtPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic")
+		}
+	}()
+	New([]int{1}).InsertAt(-1, 0)
+}
+
+func TestInterleave(t *testing.T) {
+	a := New([]int{1, 4, 7, 9})
+	b := New([]int{2, 5})
+	c := New([]int{3, 6, 8})
+	result := Interleave(a, b, c).ToList()
+	expected := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	a := New([]int{1, 4, 7})
+	b := New([]int{2, 2, 8})
+	c := New([]int{})
+	d := New([]int{0, 3, 9, 10})
+	result := MergeSorted(cmp.Compare[int], a, b, c, d).ToList()
+	expected := []int{0, 1, 2, 2, 3, 4, 7, 8, 9, 10}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestMergeSortedStable(t *testing.T) {
+	type item struct {
+		key    int
+		source string
+	}
+	a := New([]item{{1, "a"}, {2, "a"}})
+	b := New([]item{{1, "b"}, {2, "b"}})
+	result := MergeSorted(func(x, y item) int { return cmp.Compare(x.key, y.key) }, a, b).ToList()
+	expected := []item{{1, "a"}, {1, "b"}, {2, "a"}, {2, "b"}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
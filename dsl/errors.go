@@ -0,0 +1,33 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrSyntax is wrapped by errors for pipelines that cannot be parsed
+	ErrSyntax = errors.New("dsl: syntax error")
+	// ErrType is wrapped by errors for pipelines whose stages do not fit the values they are applied to
+	ErrType = errors.New("dsl: type error")
+)
+
+// Error is an error at a position in the source of a pipeline
+type Error struct {
+	Pos Pos
+	Msg string
+	// Err is ErrSyntax, ErrType or the error of the expression package that caused the error
+	Err error
+}
+
+func errorf(pos Pos, err error, format string, args ...any) *Error {
+	return &Error{pos, fmt.Sprintf(format, args...), err}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("dsl: %v: %s", e.Pos, e.Msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,220 @@
+package dsl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pos is a position in the source of a pipeline
+type Pos struct {
+	// Offset is the byte offset, starting at 0
+	Offset int
+	// Line is the line number, starting at 1
+	Line int
+	// Column is the column in runes, starting at 1
+	Column int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+type kind int
+
+const (
+	eof kind = iota
+	ident
+	field
+	number
+	str
+	symbol
+)
+
+func (k kind) String() string {
+	return [...]string{"end of input", "identifier", "field", "number", "string", "symbol"}[k]
+}
+
+type token struct {
+	kind kind
+	text string
+	pos  Pos
+}
+
+func (t token) String() string {
+	if t.kind == eof {
+		return t.kind.String()
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// symbols are the operators and punctuation, longest first
+var symbols = []string{"==", "!=", "<=", ">=", "<", ">", "|", "(", ")", "[", "]", ","}
+
+type lexer struct {
+	src  string
+	pos  Pos
+	toks []token
+}
+
+// lex splits src into tokens, ending with an eof token
+func lex(src string) ([]token, error) {
+	l := &lexer{src: src, pos: Pos{0, 1, 1}}
+	for {
+		l.skipSpace()
+		start := l.pos
+		if l.pos.Offset == len(src) {
+			l.toks = append(l.toks, token{eof, "", start})
+			return l.toks, nil
+		}
+		k, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		l.toks = append(l.toks, token{k, src[start.Offset:l.pos.Offset], start})
+	}
+}
+
+func (l *lexer) next() (kind, error) {
+	r := l.peek()
+	switch {
+	case r == '.':
+		l.advance()
+		for isIdentStart(l.peek()) {
+			l.ident()
+			if l.peek() != '.' || !isIdentStart(l.peekAfter('.')) {
+				break
+			}
+			l.advance()
+		}
+		return field, nil
+	case isIdentStart(r):
+		l.ident()
+		return ident, nil
+	case r == '-' || isDigit(r):
+		return number, l.number()
+	case r == '"':
+		return str, l.string()
+	}
+	for _, s := range symbols {
+		if strings.HasPrefix(l.src[l.pos.Offset:], s) {
+			for range s {
+				l.advance()
+			}
+			return symbol, nil
+		}
+	}
+	return eof, errorf(l.pos, ErrSyntax, "unexpected character %q", r)
+}
+
+func (l *lexer) ident() {
+	for isIdentStart(l.peek()) || isDigit(l.peek()) {
+		l.advance()
+	}
+}
+
+// number scans an optionally negative decimal number with an optional fraction and exponent
+func (l *lexer) number() error {
+	start := l.pos
+	if l.peek() == '-' {
+		l.advance()
+	}
+	if !l.digits() {
+		return errorf(start, ErrSyntax, "malformed number")
+	}
+	if l.peek() == '.' {
+		l.advance()
+		if !l.digits() {
+			return errorf(start, ErrSyntax, "malformed number")
+		}
+	}
+	if l.peek() == 'e' || l.peek() == 'E' {
+		l.advance()
+		if l.peek() == '+' || l.peek() == '-' {
+			l.advance()
+		}
+		if !l.digits() {
+			return errorf(start, ErrSyntax, "malformed number")
+		}
+	}
+	if isIdentStart(l.peek()) {
+		return errorf(start, ErrSyntax, "malformed number")
+	}
+	return nil
+}
+
+func (l *lexer) digits() bool {
+	start := l.pos.Offset
+	for isDigit(l.peek()) {
+		l.advance()
+	}
+	return l.pos.Offset > start
+}
+
+// string scans a double quoted string with Go escapes, which are interpreted by the parser
+func (l *lexer) string() error {
+	start := l.pos
+	l.advance()
+	for {
+		switch l.peek() {
+		case '"':
+			l.advance()
+			return nil
+		case '\\':
+			l.advance()
+		case '\n', utf8.RuneError:
+			if l.pos.Offset == len(l.src) || l.peek() == '\n' {
+				return errorf(start, ErrSyntax, "unterminated string")
+			}
+		}
+		l.advance()
+	}
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos.Offset < len(l.src) && unicode.IsSpace(l.peek()) {
+		l.advance()
+	}
+}
+
+// peek returns the rune at the current position, or utf8.RuneError at the end
+func (l *lexer) peek() rune {
+	if l.pos.Offset == len(l.src) {
+		return utf8.RuneError
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos.Offset:])
+	return r
+}
+
+// peekAfter returns the rune following the rune r at the current position
+func (l *lexer) peekAfter(r rune) rune {
+	rest := l.src[l.pos.Offset+utf8.RuneLen(r):]
+	if rest == "" {
+		return utf8.RuneError
+	}
+	next, _ := utf8.DecodeRuneInString(rest)
+	return next
+}
+
+func (l *lexer) advance() {
+	if l.pos.Offset == len(l.src) {
+		return
+	}
+	r, size := utf8.DecodeRuneInString(l.src[l.pos.Offset:])
+	l.pos.Offset += size
+	if r == '\n' {
+		l.pos.Line++
+		l.pos.Column = 1
+	} else {
+		l.pos.Column++
+	}
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
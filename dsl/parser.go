@@ -0,0 +1,348 @@
+package dsl
+
+import (
+	"strconv"
+
+	"github.com/sdehm/go-enumerable/expr"
+)
+
+// Stage is one stage of a parsed pipeline
+type Stage struct {
+	Pos Pos
+	// Name is the operation of the stage such as filter, map or take
+	Name string
+	// Expr is the expression of filter, map and sort stages
+	Expr expr.Expr
+	// ExprPos is the position of Expr
+	ExprPos Pos
+	// N is the count of take and skip stages
+	N int
+	// Desc is set for sort stages in descending order
+	Desc bool
+	// Aggregate is the aggregate of each group of a group stage, empty to keep the values
+	Aggregate string
+	// AggregateExpr is the expression aggregated by sum, avg, min and max in a group stage
+	AggregateExpr expr.Expr
+	// AggregatePos is the position of AggregateExpr
+	AggregatePos Pos
+}
+
+// String formats the stage in the syntax accepted by Parse
+func (s Stage) String() string {
+	switch stages[s.Name] {
+	case withExpr:
+		return s.Name + " " + s.Expr.String()
+	case withSortKey:
+		if s.Desc {
+			return s.Name + " " + s.Expr.String() + " desc"
+		}
+		return s.Name + " " + s.Expr.String()
+	case withCount:
+		return s.Name + " " + strconv.Itoa(s.N)
+	case withGroupKey:
+		switch s.Aggregate {
+		case "":
+			return s.Name + " " + s.Expr.String()
+		case "count":
+			return s.Name + " " + s.Expr.String() + " count"
+		default:
+			return s.Name + " " + s.Expr.String() + " " + s.Aggregate + " " + s.AggregateExpr.String()
+		}
+	default:
+		return s.Name
+	}
+}
+
+// IsAggregate reports whether the stage aggregates the values into a single result
+func (s Stage) IsAggregate() bool {
+	return aggregates[s.Name]
+}
+
+// stageKind describes the argument a stage takes
+type stageKind int
+
+const (
+	bare stageKind = iota
+	withExpr
+	withCount
+	withSortKey
+	withGroupKey
+)
+
+// stages are the operations of a pipeline, aggregates can only be the last stage
+var stages = map[string]stageKind{
+	"filter":  withExpr,
+	"map":     withExpr,
+	"sort":    withSortKey,
+	"group":   withGroupKey,
+	"take":    withCount,
+	"skip":    withCount,
+	"reverse": bare,
+	"count":   bare,
+	"sum":     bare,
+	"avg":     bare,
+	"min":     bare,
+	"max":     bare,
+}
+
+var aggregates = map[string]bool{"count": true, "sum": true, "avg": true, "min": true, "max": true}
+
+var comparisons = map[string]func(expr.Expr, any) expr.Expr{
+	"==": expr.Expr.Eq,
+	"!=": expr.Expr.Ne,
+	"<":  expr.Expr.Lt,
+	"<=": expr.Expr.Le,
+	">":  expr.Expr.Gt,
+	">=": expr.Expr.Ge,
+}
+
+var functions = map[string]expr.Op{
+	"hasPrefix": expr.OpPrefix,
+	"hasSuffix": expr.OpSuffix,
+	"contains":  expr.OpContains,
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+// Parse parses the stages of a pipeline separated by |, such as
+//
+//	filter .status == "error" | map .latency_ms | take 100 | avg
+//
+// filter keeps the values matching a boolean expression, map replaces values by an expression,
+// sort orders values by an expression followed by an optional asc or desc, take and skip take or skip a count of values
+// and reverse reverses them. The last stage can be one of the aggregates count, sum, avg, min and max.
+// group groups values by an expression into {"key": key, "values": [...]} maps, or aggregates each group
+// into {"key": key, "count": n} with group .key count or {"key": key, "sum": n} with group .key sum .field
+// and likewise for avg, min and max
+//
+// Expressions use the syntax printed by expr.Expr: fields such as .latency_ms or .user.name and . for the value itself,
+// string, number, true, false and null constants, the comparisons == != < <= > >= and in [...], the functions hasPrefix,
+// hasSuffix and contains, and the operators not, and and or from tightest to loosest binding
+func Parse(src string) ([]Stage, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	var result []Stage
+	for {
+		s, err := p.stage()
+		if err != nil {
+			return nil, err
+		}
+		if len(result) > 0 && aggregates[result[len(result)-1].Name] {
+			last := result[len(result)-1]
+			return nil, errorf(s.Pos, ErrSyntax, "%s must be the last stage", last.Name)
+		}
+		result = append(result, s)
+		if p.peek().kind == eof {
+			return result, nil
+		}
+		if _, err := p.expect("|"); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) stage() (Stage, error) {
+	t := p.next()
+	kind, ok := stages[t.text]
+	if t.kind != ident || !ok {
+		return Stage{}, errorf(t.pos, ErrSyntax, "expected a stage, got %v", t)
+	}
+	s := Stage{Pos: t.pos, Name: t.text}
+	var err error
+	switch kind {
+	case withExpr, withSortKey, withGroupKey:
+		s.ExprPos = p.peek().pos
+		if s.Expr, err = p.or(); err != nil {
+			return s, err
+		}
+		next := p.peek()
+		if kind == withSortKey && next.kind == ident && (next.text == "asc" || next.text == "desc") {
+			s.Desc = p.next().text == "desc"
+		}
+		if kind == withGroupKey && next.kind == ident && aggregates[next.text] {
+			s.Aggregate = p.next().text
+			if s.Aggregate != "count" {
+				s.AggregatePos = p.peek().pos
+				if s.AggregateExpr, err = p.or(); err != nil {
+					return s, err
+				}
+			}
+		}
+	case withCount:
+		n := p.next()
+		count, convErr := strconv.Atoi(n.text)
+		if n.kind != number || convErr != nil || count < 0 {
+			return s, errorf(n.pos, ErrSyntax, "expected a count, got %v", n)
+		}
+		s.N = count
+	}
+	return s, nil
+}
+
+func (p *parser) or() (expr.Expr, error) {
+	return p.logical("or", expr.Expr.Or, p.and)
+}
+
+func (p *parser) and() (expr.Expr, error) {
+	return p.logical("and", expr.Expr.And, p.not)
+}
+
+// logical parses operands separated by the keyword op into a single node
+func (p *parser) logical(op string, combine func(expr.Expr, ...expr.Expr) expr.Expr, operand func() (expr.Expr, error)) (expr.Expr, error) {
+	first, err := operand()
+	if err != nil {
+		return first, err
+	}
+	var rest []expr.Expr
+	for p.peek().kind == ident && p.peek().text == op {
+		p.next()
+		e, err := operand()
+		if err != nil {
+			return e, err
+		}
+		rest = append(rest, e)
+	}
+	if len(rest) == 0 {
+		return first, nil
+	}
+	return combine(first, rest...), nil
+}
+
+func (p *parser) not() (expr.Expr, error) {
+	if p.peek().kind == ident && p.peek().text == "not" {
+		p.next()
+		e, err := p.not()
+		return e.Not(), err
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (expr.Expr, error) {
+	left, err := p.primary()
+	if err != nil {
+		return left, err
+	}
+	t := p.peek()
+	if compare, ok := comparisons[t.text]; ok && t.kind == symbol {
+		p.next()
+		right, err := p.primary()
+		return compare(left, right), err
+	}
+	if t.kind == ident && t.text == "in" {
+		p.next()
+		values, err := p.list("[", "]")
+		args := make([]any, len(values))
+		for i, v := range values {
+			args[i] = v
+		}
+		return left.In(args...), err
+	}
+	return left, nil
+}
+
+func (p *parser) primary() (expr.Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case field:
+		return expr.Field(t.text[1:]), nil
+	case number:
+		if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return expr.Const(i), nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return expr.Expr{}, errorf(t.pos, ErrSyntax, "number %s is out of range", t.text)
+		}
+		return expr.Const(f), nil
+	case str:
+		s, err := strconv.Unquote(t.text)
+		if err != nil {
+			return expr.Expr{}, errorf(t.pos, ErrSyntax, "malformed string %s", t.text)
+		}
+		return expr.Const(s), nil
+	case ident:
+		switch t.text {
+		case "true", "false":
+			return expr.Const(t.text == "true"), nil
+		case "null":
+			return expr.Const(nil), nil
+		}
+		if op, ok := functions[t.text]; ok {
+			args, err := p.list("(", ")")
+			if err == nil && len(args) != 2 {
+				err = errorf(t.pos, ErrSyntax, "%s takes 2 arguments, got %d", t.text, len(args))
+			}
+			return expr.Expr{Op: op, Args: args}, err
+		}
+	case symbol:
+		if t.text == "(" {
+			e, err := p.or()
+			if err != nil {
+				return e, err
+			}
+			_, err = p.expect(")")
+			return e, err
+		}
+	}
+	return expr.Expr{}, errorf(t.pos, ErrSyntax, "expected an expression, got %v", t)
+}
+
+// list parses expressions separated by commas between open and close
+func (p *parser) list(open, close string) ([]expr.Expr, error) {
+	if _, err := p.expect(open); err != nil {
+		return nil, err
+	}
+	var values []expr.Expr
+	if p.peek().kind == symbol && p.peek().text == close {
+		p.next()
+		return values, nil
+	}
+	for {
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, e)
+		t, err := p.expect(",", close)
+		if err != nil {
+			return nil, err
+		}
+		if t.text == close {
+			return values, nil
+		}
+	}
+}
+
+// expect consumes the next token if it is one of the symbols
+func (p *parser) expect(symbols ...string) (token, error) {
+	t := p.next()
+	for _, s := range symbols {
+		if t.kind == symbol && t.text == s {
+			return t, nil
+		}
+	}
+	expected := strconv.Quote(symbols[0])
+	for _, s := range symbols[1:] {
+		expected += " or " + strconv.Quote(s)
+	}
+	return t, errorf(t.pos, ErrSyntax, "expected %s, got %v", expected, t)
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+// next consumes a token, the final eof token is never consumed
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != eof {
+		p.i++
+	}
+	return t
+}
@@ -0,0 +1,138 @@
+package dsl
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sdehm/go-enumerable/expr"
+)
+
+func TestParse(t *testing.T) {
+	stages, err := Parse(`filter .status == "error" | map .latency_ms | take 100 | avg`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Stage{
+		{Pos: Pos{0, 1, 1}, Name: "filter", Expr: expr.Field("status").Eq("error"), ExprPos: Pos{7, 1, 8}},
+		{Pos: Pos{28, 1, 29}, Name: "map", Expr: expr.Field("latency_ms"), ExprPos: Pos{32, 1, 33}},
+		{Pos: Pos{46, 1, 47}, Name: "take", N: 100},
+		{Pos: Pos{57, 1, 58}, Name: "avg"},
+	}
+
+	if !reflect.DeepEqual(stages, expected) {
+		t.Errorf("Expected %v, got %v", expected, stages)
+	}
+}
+
+func TestParseExpressions(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected expr.Expr
+	}{
+		{`.a.b`, expr.Field("a.b")},
+		{`.`, expr.Field("")},
+		{`. > -1.5e3`, expr.Field("").Gt(-1.5e3)},
+		{`.a == 1 and .b != "x" or not .c`, expr.Field("a").Eq(int64(1)).And(expr.Field("b").Ne("x")).Or(expr.Field("c").Not())},
+		{`.a and (.b or .c) and .d`, expr.Field("a").And(expr.Field("b").Or(expr.Field("c")), expr.Field("d"))},
+		{`not not .a`, expr.Field("a").Not().Not()},
+		{`.a in [1, "two", null]`, expr.Field("a").In(int64(1), "two", nil)},
+		{`.a in []`, expr.Field("a").In()},
+		{`hasPrefix(.name, "A") or hasSuffix(.name, "z") or contains(.name, "\"")`,
+			expr.Field("name").HasPrefix("A").Or(expr.Field("name").HasSuffix("z"), expr.Field("name").Contains(`"`))},
+		{`.x <= .y`, expr.Field("x").Le(expr.Field("y"))},
+		{`.flag == true`, expr.Field("flag").Eq(true)},
+		{`.é >= 2`, expr.Field("é").Ge(int64(2))},
+	}
+	for _, tt := range tests {
+		stages, err := Parse("filter " + tt.src)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(stages[0].Expr, tt.expected) {
+			t.Errorf("Expected %v for %s, got %v", tt.expected, tt.src, stages[0].Expr)
+		}
+	}
+}
+
+func TestParseStages(t *testing.T) {
+	src := "filter .a | map .b | sort .c desc | sort .d asc | take 1 | skip 2 | reverse | group .e | group .f count | group .g min .h | count"
+	stages, err := Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := make([]string, len(stages))
+	for i, s := range stages {
+		result[i] = s.String()
+	}
+	expected := "filter .a | map .b | sort .c desc | sort .d | take 1 | skip 2 | reverse | group .e | group .f count | group .g min .h | count"
+
+	if strings.Join(result, " | ") != expected {
+		t.Errorf("Expected %s, got %s", expected, strings.Join(result, " | "))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{``, `dsl: 1:1: expected a stage, got end of input`},
+		{`filter`, `dsl: 1:7: expected an expression, got end of input`},
+		{`fitler .a`, `dsl: 1:1: expected a stage, got "fitler"`},
+		{`filter .a ==`, `dsl: 1:13: expected an expression, got end of input`},
+		{`filter .a == 1 == 2`, `dsl: 1:16: expected "|", got "=="`},
+		{"filter .a\n| take x", `dsl: 2:8: expected a count, got "x"`},
+		{`take -1`, `dsl: 1:6: expected a count, got "-1"`},
+		{`count | take 1`, `dsl: 1:9: count must be the last stage`},
+		{`filter .a in [1 2]`, `dsl: 1:17: expected "," or "]", got "2"`},
+		{`filter (.a`, `dsl: 1:11: expected ")", got end of input`},
+		{`filter "abc`, `dsl: 1:8: unterminated string`},
+		{`filter "\q"`, `dsl: 1:8: malformed string "\q"`},
+		{`filter .a == 1x`, `dsl: 1:14: malformed number`},
+		{`filter .a == 1e999`, `dsl: 1:14: number 1e999 is out of range`},
+		{`filter .a = 1`, `dsl: 1:11: unexpected character '='`},
+		{`filter hasPrefix(.a)`, `dsl: 1:8: hasPrefix takes 2 arguments, got 1`},
+		{`map . |`, `dsl: 1:8: expected a stage, got end of input`},
+		{`group .a sum`, `dsl: 1:13: expected an expression, got end of input`},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("Expected %s for %q, got %v", tt.expected, tt.src, err)
+		}
+		if !errors.Is(err, ErrSyntax) {
+			t.Errorf("Expected %v for %q, got %v", ErrSyntax, tt.src, err)
+		}
+	}
+}
+
+// FuzzParse checks that the parser never panics and that formatting a parsed pipeline gives a
+// pipeline that parses to the same stages
+func FuzzParse(f *testing.F) {
+	f.Add(`filter .status == "error" | map .latency_ms | take 100 | avg`)
+	f.Add(`filter not (.a < 1 or .b >= 2.5) and .c in [1, "x", null] | sort .d desc | skip 3 | reverse | max`)
+	f.Add(`filter hasPrefix(.name, "A\n") and contains(., "é") | map . | count`)
+	f.Add(`filter .a == (.b != true) | sort -1e3`)
+	f.Add(`group .a | group .b count | group .c avg .d | map .key`)
+	f.Fuzz(func(t *testing.T, src string) {
+		stages, err := Parse(src)
+		if err != nil {
+			var e *Error
+			if !errors.As(err, &e) || e.Pos.Offset > len(src) {
+				t.Fatalf("Expected a positioned error for %q, got %v", src, err)
+			}
+			return
+		}
+		formatted := (&Pipeline[any]{stages}).String()
+		reparsed, err := Parse(formatted)
+		if err != nil {
+			t.Fatalf("Formatted %q as %q which does not parse: %v", src, formatted, err)
+		}
+		if again := (&Pipeline[any]{reparsed}).String(); again != formatted {
+			t.Fatalf("Formatted %q as %q then %q", src, formatted, again)
+		}
+	})
+}
@@ -0,0 +1,364 @@
+// Package dsl compiles pipelines written as text, such as
+//
+//	filter .status == "error" | map .latency_ms | take 100 | avg
+//
+// into Enumerable pipelines over maps with string keys, structs or any other values
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	enumerable "github.com/sdehm/go-enumerable"
+	"github.com/sdehm/go-enumerable/expr"
+)
+
+// Pipeline is a parsed and type checked pipeline over values of type T
+type Pipeline[T any] struct {
+	stages []Stage
+}
+
+// Compile parses src and type checks its stages against values of type T
+// Fields of map[string]any values and values mapped from them are only checked when the pipeline is run
+func Compile[T any](src string) (*Pipeline[T], error) {
+	stages, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := check(stages, reflect.TypeFor[T]()); err != nil {
+		return nil, err
+	}
+	return &Pipeline[T]{stages}, nil
+}
+
+// Stages returns the stages of the pipeline
+func (p *Pipeline[T]) Stages() []Stage {
+	return slices.Clone(p.stages)
+}
+
+// String formats the pipeline in the syntax accepted by Parse
+func (p *Pipeline[T]) String() string {
+	parts := make([]string, len(p.stages))
+	for i, s := range p.stages {
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, " | ")
+}
+
+func check(stages []Stage, t reflect.Type) error {
+	for _, s := range stages {
+		if t != nil && t.Kind() == reflect.Interface {
+			t = nil
+		}
+		switch s.Name {
+		case "filter", "map", "sort":
+			result, err := expr.Check(s.Expr, t)
+			if err != nil {
+				return &Error{s.ExprPos, err.Error(), fmt.Errorf("%w: %w", ErrType, err)}
+			}
+			switch {
+			case s.Name == "map":
+				t = result
+			case result == nil:
+			case s.Name == "filter" && result.Kind() != reflect.Bool:
+				return errorf(s.ExprPos, ErrType, "filter needs a boolean expression, %v is %v", s.Expr, result)
+			case s.Name == "sort" && !ordered(result):
+				return errorf(s.ExprPos, ErrType, "cannot sort by %v, it is %v", s.Expr, result)
+			}
+		case "group":
+			if _, err := expr.Check(s.Expr, t); err != nil {
+				return &Error{s.ExprPos, err.Error(), fmt.Errorf("%w: %w", ErrType, err)}
+			}
+			if s.Aggregate != "" && s.Aggregate != "count" {
+				result, err := expr.Check(s.AggregateExpr, t)
+				if err != nil {
+					return &Error{s.AggregatePos, err.Error(), fmt.Errorf("%w: %w", ErrType, err)}
+				}
+				if err := checkAggregate(s.Aggregate, result, s.AggregatePos); err != nil {
+					return err
+				}
+			}
+			t = nil
+		default:
+			if err := checkAggregate(s.Name, t, s.Pos); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkAggregate checks that the aggregate name can be applied to values of type t
+func checkAggregate(name string, t reflect.Type, pos Pos) error {
+	switch {
+	case t == nil:
+	case (name == "sum" || name == "avg") && !numeric(t):
+		return errorf(pos, ErrType, "%s needs numbers, values are %v", name, t)
+	case (name == "min" || name == "max") && !ordered(t):
+		return errorf(pos, ErrType, "%s needs ordered values, values are %v", name, t)
+	}
+	return nil
+}
+
+func numeric(t reflect.Type) bool {
+	return t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64
+}
+
+func ordered(t reflect.Type) bool {
+	return numeric(t) || t.Kind() == reflect.String || t == reflect.TypeFor[time.Time]()
+}
+
+// Apply applies the stages before any aggregate to e
+// The values are the results of the last map or group stage, or the values of e if there is none
+// Evaluates lazily, errors evaluating expressions are returned by ToListErr and ForEachErr
+func (p *Pipeline[T]) Apply(e enumerable.Enumerable[T]) enumerable.Enumerable[any] {
+	stages := p.stages
+	if aggregates[stages[len(stages)-1].Name] {
+		stages = stages[:len(stages)-1]
+	}
+	// stages before the first map or group run on the values of e so that fields of structs are only resolved once
+	i := 0
+	for ; i < len(stages) && !changesType(stages[i]); i++ {
+		e = apply(e, stages[i])
+	}
+	if i == len(stages) {
+		return e.MapExpr(expr.Field(""))
+	}
+	values := applyChange(e, stages[i])
+	for _, s := range stages[i+1:] {
+		if changesType(s) {
+			values = applyChange(values, s)
+		} else {
+			values = apply(values, s)
+		}
+	}
+	return values
+}
+
+func changesType(s Stage) bool {
+	return s.Name == "map" || s.Name == "group"
+}
+
+// applyChange applies a map or group stage
+func applyChange[T any](e enumerable.Enumerable[T], s Stage) enumerable.Enumerable[any] {
+	if s.Name == "map" {
+		return e.MapExpr(s.Expr)
+	}
+	return group(e, s)
+}
+
+func apply[T any](e enumerable.Enumerable[T], s Stage) enumerable.Enumerable[T] {
+	switch s.Name {
+	case "filter":
+		return e.FilterExpr(s.Expr)
+	case "sort":
+		return sortBy(e, s.Expr, s.Desc)
+	case "take":
+		return e.Take(s.N)
+	case "skip":
+		return e.Skip(s.N)
+	default:
+		return e.Reverse()
+	}
+}
+
+type keyed[T any] struct {
+	key   any
+	value T
+}
+
+// sortBy stably sorts e by key using expr.Compare
+func sortBy[T any](e enumerable.Enumerable[T], key expr.Expr, desc bool) enumerable.Enumerable[T] {
+	eval, err := expr.Compile[T](key)
+	if err != nil {
+		return enumerable.TransformErr(e, func(T) (T, error) {
+			var zero T
+			return zero, err
+		})
+	}
+	pairs := enumerable.TransformErr(e, func(v T) (keyed[T], error) {
+		k, err := eval(v)
+		return keyed[T]{k, v}, err
+	})
+	sorted := pairs.OrderBy(func(a, b keyed[T]) int {
+		if desc {
+			return expr.Compare(b.key, a.key)
+		}
+		return expr.Compare(a.key, b.key)
+	})
+	return enumerable.TransformErr(sorted, func(k keyed[T]) (T, error) {
+		return k.value, nil
+	})
+}
+
+// group groups e by the key of the stage into maps of the key and the values or their aggregate
+func group[T any](e enumerable.Enumerable[T], s Stage) enumerable.Enumerable[any] {
+	key, err := expr.Compile[T](s.Expr)
+	if err != nil {
+		return enumerable.TransformErr(e, func(T) (any, error) { return nil, err })
+	}
+	pairs := enumerable.TransformErr(e, func(v T) (keyed[T], error) {
+		k, err := key(v)
+		if err == nil && k != nil && !reflect.ValueOf(k).Comparable() {
+			err = fmt.Errorf("%w: cannot group by %v of type %T", ErrType, k, k)
+		}
+		return keyed[T]{k, v}, err
+	})
+	groups := enumerable.GroupBy(pairs, func(k keyed[T]) any { return k.key })
+	return enumerable.TransformErr(groups, func(g enumerable.Group[any, keyed[T]]) (any, error) {
+		values := make([]T, len(g.Values))
+		for i, v := range g.Values {
+			values[i] = v.value
+		}
+		if s.Aggregate == "" {
+			list, err := enumerable.New(values).MapExpr(expr.Field("")).ToListErr()
+			return map[string]any{"key": g.Key, "values": list}, err
+		}
+		agg := s.AggregateExpr
+		if s.Aggregate == "count" {
+			agg = expr.Field("")
+		}
+		result, err := aggregate(enumerable.New(values).MapExpr(agg), s.Aggregate)
+		if errors.Is(err, enumerable.ErrEmpty) {
+			// a group whose values are all null has a null aggregate
+			result, err = nil, nil
+		}
+		return map[string]any{"key": g.Key, s.Aggregate: result}, err
+	})
+}
+
+// Run evaluates the pipeline over e, returning the result of the aggregate or the values as a []any
+// count returns an int, sum an int64 if every value is an integer and the sum fits and a float64 otherwise,
+// avg a float64 and min and max the smallest and largest value. Aggregates skip null values, avg, min and max
+// return enumerable.ErrEmpty when there are no values
+func (p *Pipeline[T]) Run(e enumerable.Enumerable[T]) (any, error) {
+	values := p.Apply(e)
+	last := p.stages[len(p.stages)-1]
+	if !aggregates[last.Name] {
+		list, err := values.ToListErr()
+		return list, err
+	}
+	return aggregate(values, last.Name)
+}
+
+// aggregate applies the aggregate name to the values that are not null
+func aggregate(values enumerable.Enumerable[any], name string) (any, error) {
+	values = values.FilterExpr(expr.Field("").Ne(nil))
+	switch name {
+	case "count":
+		count := 0
+		err := values.ForEachErr(func(any) { count++ })
+		return count, err
+	case "sum", "avg":
+		return sum(values, name == "avg")
+	default:
+		return extreme(values, name == "max")
+	}
+}
+
+// sum adds up or averages the numbers in a single pass without holding them
+// Integers are summed as int64 unless the sum overflows, any float makes every value a float64
+func sum(values enumerable.Enumerable[any], avg bool) (any, error) {
+	var t total
+	err := enumerable.TransformErr(values, func(v any) (reflect.Value, error) {
+		n := reflect.ValueOf(v)
+		if !numeric(n.Type()) {
+			return n, fmt.Errorf("%w: cannot sum %v of type %T", ErrType, v, v)
+		}
+		return n, nil
+	}).ForEachErr(t.add)
+	switch {
+	case err != nil:
+		return nil, err
+	case avg && t.count == 0:
+		return nil, enumerable.ErrEmpty
+	case avg:
+		return t.float() / float64(t.count), nil
+	}
+	if i, ok := t.int64(); ok && !t.isFloat {
+		return i, nil
+	}
+	return t.float(), nil
+}
+
+// total is a running sum, integers are added exactly in 128 bit two's complement and floats with Kahan compensation
+type total struct {
+	hi, lo       uint64
+	floats       float64
+	compensation float64
+	isFloat      bool
+	count        int
+}
+
+func (t *total) add(n reflect.Value) {
+	t.count++
+	switch {
+	case n.CanInt():
+		var hi uint64
+		if n.Int() < 0 {
+			// sign extend negative values into the high word
+			hi = math.MaxUint64
+		}
+		t.addWide(hi, uint64(n.Int()))
+	case n.CanUint():
+		t.addWide(0, n.Uint())
+	default:
+		t.isFloat = true
+		y := n.Float() - t.compensation
+		sum := t.floats + y
+		t.compensation = (sum - t.floats) - y
+		t.floats = sum
+	}
+}
+
+func (t *total) addWide(hi, lo uint64) {
+	var carry uint64
+	t.lo, carry = bits.Add64(t.lo, lo, 0)
+	t.hi, _ = bits.Add64(t.hi, hi, carry)
+}
+
+// int64 returns the sum of the integers if it fits in an int64
+func (t *total) int64() (int64, bool) {
+	negative := int64(t.lo) < 0
+	if (negative && t.hi == math.MaxUint64) || (!negative && t.hi == 0) {
+		return int64(t.lo), true
+	}
+	return 0, false
+}
+
+// float returns the sum of the integers and floats as a float64
+func (t *total) float() float64 {
+	hi, lo := t.hi, t.lo
+	negative := int64(hi) < 0
+	if negative {
+		// negate to convert the magnitude, which keeps the precision of small negative sums
+		var borrow uint64
+		lo, borrow = bits.Sub64(0, lo, 0)
+		hi, _ = bits.Sub64(0, hi, borrow)
+	}
+	ints := float64(hi)*(1<<64) + float64(lo)
+	if negative {
+		ints = -ints
+	}
+	return ints + t.floats
+}
+
+func extreme(values enumerable.Enumerable[any], max bool) (any, error) {
+	var result any
+	err := values.ForEachErr(func(v any) {
+		c := expr.Compare(v, result)
+		if result == nil || max && c > 0 || !max && c < 0 {
+			result = v
+		}
+	})
+	if err == nil && result == nil {
+		err = enumerable.ErrEmpty
+	}
+	return result, err
+}
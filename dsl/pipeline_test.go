@@ -0,0 +1,198 @@
+package dsl
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	enumerable "github.com/sdehm/go-enumerable"
+	"github.com/sdehm/go-enumerable/expr"
+)
+
+var logs = []map[string]any{
+	{"status": "error", "latency_ms": 120, "path": "/a"},
+	{"status": "ok", "latency_ms": 15, "path": "/b"},
+	{"status": "error", "latency_ms": 80.5, "path": "/c"},
+	{"status": "error", "path": "/d"},
+	{"status": "ok", "latency_ms": 40, "path": "/e"},
+}
+
+type request struct {
+	Status    string
+	LatencyMS int
+	Path      string
+}
+
+var requests = []request{{"error", 120, "/a"}, {"ok", 15, "/b"}, {"error", 80, "/c"}, {"ok", 40, "/e"}}
+
+func run[T any](t *testing.T, src string, values []T) (any, error) {
+	t.Helper()
+	p, err := Compile[T](src)
+	if err != nil {
+		t.Fatalf("Unexpected error compiling %s: %v", src, err)
+	}
+	return p.Run(enumerable.New(values))
+}
+
+func TestRunMaps(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected any
+	}{
+		{`filter .status == "error" | map .latency_ms | take 100 | avg`, 100.25},
+		{`filter .status == "error" | map .latency_ms | sum`, 200.5},
+		{`filter .status == "ok" | map .latency_ms | sum`, int64(55)},
+		{`map .latency_ms | max`, 120},
+		{`map .latency_ms | min`, 15},
+		{`filter .latency_ms == null | count`, 1},
+		{`map .latency_ms | count`, 4},
+		{`filter .latency_ms == null | map .path`, []any{"/d"}},
+		{`filter .latency_ms > 30 | count`, 3},
+		{`sort .latency_ms desc | map .path | take 2`, []any{"/a", "/c"}},
+		{`sort .status | map .path`, []any{"/a", "/c", "/d", "/b", "/e"}},
+		{`map .path | reverse | skip 3`, []any{"/b", "/a"}},
+		{`map .status | filter hasPrefix(., "e") | count`, 3},
+		{`filter .path in ["/a", "/e"] | map .path`, []any{"/a", "/e"}},
+		{`group .status count`, []any{map[string]any{"key": "error", "count": 3}, map[string]any{"key": "ok", "count": 2}}},
+		{`group .status avg .latency_ms | sort .avg`, []any{map[string]any{"key": "ok", "avg": 27.5}, map[string]any{"key": "error", "avg": 100.25}}},
+		{`filter .latency_ms == null | group .path max .latency_ms`, []any{map[string]any{"key": "/d", "max": nil}}},
+		{`group .status | map .key`, []any{"error", "ok"}},
+		{`map .latency_ms > 50 | group . count | count`, 3},
+	}
+	for _, tt := range tests {
+		result, err := run(t, tt.src, logs)
+		if err != nil || !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Expected %v for %s, got %v (%T), %v", tt.expected, tt.src, result, result, err)
+		}
+	}
+}
+
+func TestRunOverflow(t *testing.T) {
+	tests := []struct {
+		src      string
+		values   []any
+		expected any
+	}{
+		{`avg`, []any{int64(math.MaxInt64), int64(math.MaxInt64)}, float64(math.MaxInt64)},
+		{`sum`, []any{int64(math.MaxInt64), int64(math.MaxInt64)}, 2 * float64(math.MaxInt64)},
+		{`sum`, []any{int64(math.MinInt64), int64(-1)}, float64(math.MinInt64) - 1},
+		{`sum`, []any{int64(math.MaxInt64), int64(-1)}, int64(math.MaxInt64 - 1)},
+		{`sum`, []any{uint64(math.MaxUint64), 1}, float64(math.MaxUint64) + 1},
+		{`avg`, []any{uint64(math.MaxUint64), uint64(math.MaxUint64)}, float64(math.MaxUint64)},
+		{`sum`, []any{0.1, 0.2, 0.3, -0.6}, 0.0},
+		{`sum`, []any{int64(math.MaxInt64), 1, -1}, int64(math.MaxInt64)},
+		{`sum`, []any{int64(math.MaxInt64), int64(math.MaxInt64), int64(math.MinInt64), int64(math.MinInt64)}, int64(-2)},
+		{`sum`, []any{uint64(math.MaxUint64), int64(math.MinInt64), int64(math.MinInt64)}, int64(-1)},
+		{`avg`, []any{int64(math.MinInt64), int64(math.MinInt64)}, float64(math.MinInt64)},
+		{`sum`, []any{int64(math.MinInt64), int64(math.MinInt64), 0.5}, 2*float64(math.MinInt64) + 0.5},
+		{`sum`, []any{int64(-5), 0.5}, -4.5},
+	}
+	for _, tt := range tests {
+		result, err := run(t, tt.src, tt.values)
+		if err != nil || result != tt.expected {
+			t.Errorf("Expected %v for %s of %v, got %v (%T), %v", tt.expected, tt.src, tt.values, result, result, err)
+		}
+	}
+}
+
+func TestRunStructs(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected any
+	}{
+		{`filter .Status == "error" | map .LatencyMS | take 100 | avg`, 100.0},
+		{`sort .LatencyMS | map .Path`, []any{"/b", "/e", "/c", "/a"}},
+		{`filter .LatencyMS >= 40 and not hasSuffix(.Path, "a") | map .Path`, []any{"/c", "/e"}},
+		{`map .Status | max`, "ok"},
+		{`filter .LatencyMS < 100 | group .Status`, []any{
+			map[string]any{"key": "ok", "values": []any{requests[1], requests[3]}},
+			map[string]any{"key": "error", "values": []any{requests[2]}},
+		}},
+		{`group .Status sum .LatencyMS`, []any{map[string]any{"key": "error", "sum": int64(200)}, map[string]any{"key": "ok", "sum": int64(55)}}},
+	}
+	for _, tt := range tests {
+		result, err := run(t, tt.src, requests)
+		if err != nil || !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Expected %v for %s, got %v, %v", tt.expected, tt.src, result, err)
+		}
+	}
+}
+
+func TestRunScalars(t *testing.T) {
+	result, err := run(t, `filter . > 2 | sort . desc`, []int{3, 1, 4, 1, 5})
+	expected := []any{5, 4, 3}
+
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v, %v", expected, result, err)
+	}
+}
+
+func TestRunEmpty(t *testing.T) {
+	for _, src := range []string{`filter .status == "none" | map .latency_ms | avg`, `map .latency_ms | filter . > 1000 | max`} {
+		if _, err := run(t, src, logs); !errors.Is(err, enumerable.ErrEmpty) {
+			t.Errorf("Expected %v for %s, got %v", enumerable.ErrEmpty, src, err)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		src    string
+		values []map[string]any
+		err    error
+	}{
+		{`map .path | sum`, logs, ErrType},
+		{`filter .latency_ms > "a"`, logs, expr.ErrType},
+		{`map .path.x`, logs, expr.ErrType},
+		{`sort .path.x`, logs, expr.ErrType},
+		{`group .list`, []map[string]any{{"list": []any{1}}}, ErrType},
+	}
+	for _, tt := range tests {
+		if _, err := run(t, tt.src, tt.values); !errors.Is(err, tt.err) {
+			t.Errorf("Expected %v for %s, got %v", tt.err, tt.src, err)
+		}
+	}
+}
+
+func TestCompileTypeErrors(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{`filter .Missing == 1`, `dsl: 1:8: expr: type error: dsl.request has no field "Missing"`},
+		{`filter .LatencyMS`, `dsl: 1:8: filter needs a boolean expression, .LatencyMS is int`},
+		{`map .Status | sum`, `dsl: 1:15: sum needs numbers, values are string`},
+		{"filter .Status == \"ok\"\n  | map .Status == \"ok\" | max", `dsl: 2:27: max needs ordered values, values are bool`},
+		{`sort .LatencyMS > 3`, `dsl: 1:6: cannot sort by .LatencyMS > 3, it is bool`},
+		{`group .Status sum .Path`, `dsl: 1:19: sum needs numbers, values are string`},
+		{`group .Missing`, `dsl: 1:7: expr: type error: dsl.request has no field "Missing"`},
+		{`filter .Path > 3`, `dsl: 1:8: expr: type error: cannot compare string and int64 in .Path > 3`},
+	}
+	for _, tt := range tests {
+		_, err := Compile[request](tt.src)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("Expected %s, got %v", tt.expected, err)
+		}
+		if !errors.Is(err, ErrType) {
+			t.Errorf("Expected %v, got %v", ErrType, err)
+		}
+	}
+}
+
+func TestApplyIsLazy(t *testing.T) {
+	p, err := Compile[int](`map . | take 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	e := enumerable.New([]int{1, 2, 3, 4}).Map(func(i int) int {
+		calls++
+		return i
+	})
+	result := p.Apply(e).ToList()
+
+	if !reflect.DeepEqual(result, []any{1, 2}) || calls != 2 {
+		t.Errorf("Expected [1 2] after 2 calls, got %v after %d", result, calls)
+	}
+}
@@ -0,0 +1,150 @@
+package enumerable
+
+// First returns the first value of the Enumerable[T]
+// Returns false if the Enumerable[T] is empty
+// Only evaluates as many values as needed to find the first
+func (e Enumerable[T]) First() (T, bool) {
+	s := newScope()
+	defer s.close()
+	return e.run().iterate(s)()
+}
+
+// FirstOrDefault returns the first value of the Enumerable[T] or fallback if it is empty
+func (e Enumerable[T]) FirstOrDefault(fallback T) T {
+	if v, ok := e.First(); ok {
+		return v
+	}
+	return fallback
+}
+
+// FirstOrErr returns the first value of the Enumerable[T] or ErrEmpty if it is empty
+func (e Enumerable[T]) FirstOrErr() (T, error) {
+	v, ok := e.First()
+	if !ok {
+		return v, ErrEmpty
+	}
+	return v, nil
+}
+
+// FirstWhere returns the first value of the Enumerable[T] that satisfies the predicate
+// Returns false if no value satisfies the predicate
+// Only evaluates as many values as needed to find a match
+func (e Enumerable[T]) FirstWhere(f func(T) bool) (T, bool) {
+	return e.Filter(f).First()
+}
+
+// FirstWhereOrDefault returns the first value that satisfies the predicate or fallback if none do
+func (e Enumerable[T]) FirstWhereOrDefault(f func(T) bool, fallback T) T {
+	if v, ok := e.FirstWhere(f); ok {
+		return v
+	}
+	return fallback
+}
+
+// FirstWhereOrErr returns the first value that satisfies the predicate or ErrEmpty if none do
+func (e Enumerable[T]) FirstWhereOrErr(f func(T) bool) (T, error) {
+	v, ok := e.FirstWhere(f)
+	if !ok {
+		return v, ErrEmpty
+	}
+	return v, nil
+}
+
+// Last returns the last value of the Enumerable[T]
+// Returns false if the Enumerable[T] is empty
+func (e Enumerable[T]) Last() (T, bool) {
+	e = e.run()
+	if e.source == nil {
+		if len(e.values) == 0 {
+			var zero T
+			return zero, false
+		}
+		return e.values[len(e.values)-1], true
+	}
+	s := newScope()
+	defer s.close()
+	next := e.iterate(s)
+	last, found := next()
+	for v, ok := next(); ok; v, ok = next() {
+		last = v
+	}
+	return last, found
+}
+
+// LastOrDefault returns the last value of the Enumerable[T] or fallback if it is empty
+func (e Enumerable[T]) LastOrDefault(fallback T) T {
+	if v, ok := e.Last(); ok {
+		return v
+	}
+	return fallback
+}
+
+// LastOrErr returns the last value of the Enumerable[T] or ErrEmpty if it is empty
+func (e Enumerable[T]) LastOrErr() (T, error) {
+	v, ok := e.Last()
+	if !ok {
+		return v, ErrEmpty
+	}
+	return v, nil
+}
+
+// Single returns the only value of the Enumerable[T]
+// Returns false if the Enumerable[T] is empty or has more than one value
+// Stops evaluating after the second value
+func (e Enumerable[T]) Single() (T, bool) {
+	v, err := e.SingleOrErr()
+	return v, err == nil
+}
+
+// SingleOrDefault returns the only value of the Enumerable[T] or fallback if there is not exactly one
+func (e Enumerable[T]) SingleOrDefault(fallback T) T {
+	if v, ok := e.Single(); ok {
+		return v
+	}
+	return fallback
+}
+
+// SingleOrErr returns the only value of the Enumerable[T]
+// Returns ErrEmpty if the Enumerable[T] is empty or ErrMultiple if it has more than one value
+func (e Enumerable[T]) SingleOrErr() (T, error) {
+	s := newScope()
+	defer s.close()
+	next := e.run().iterate(s)
+	v, ok := next()
+	if !ok {
+		return v, ErrEmpty
+	}
+	if _, ok := next(); ok {
+		var zero T
+		return zero, ErrMultiple
+	}
+	return v, nil
+}
+
+// ElementAt returns the value at index i of the Enumerable[T]
+// Returns false if i is out of range
+// Only evaluates values up to index i
+func (e Enumerable[T]) ElementAt(i int) (T, bool) {
+	if i < 0 {
+		var zero T
+		return zero, false
+	}
+	return e.Skip(i).First()
+}
+
+// ElementAtOrDefault returns the value at index i of the Enumerable[T] or fallback if i is out of range
+func (e Enumerable[T]) ElementAtOrDefault(i int, fallback T) T {
+	if v, ok := e.ElementAt(i); ok {
+		return v
+	}
+	return fallback
+}
+
+// ElementAtOrErr returns the value at index i of the Enumerable[T] or ErrOutOfRange if i is out of range
+func (e Enumerable[T]) ElementAtOrErr(i int) (T, error) {
+	v, ok := e.ElementAt(i)
+	if !ok {
+		return v, ErrOutOfRange
+	}
+	return v, nil
+}
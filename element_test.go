@@ -0,0 +1,140 @@
+package enumerable
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFirst(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result, ok := e.First()
+
+	if !ok {
+		t.Errorf("Expected true, got false")
+	}
+	if result != 1 {
+		t.Errorf("Expected %d, got %d", 1, result)
+	}
+}
+
+func TestFirstEmpty(t *testing.T) {
+	e := New([]int{})
+
+	if _, ok := e.First(); ok {
+		t.Errorf("Expected false, got true")
+	}
+	if result := e.FirstOrDefault(7); result != 7 {
+		t.Errorf("Expected %d, got %d", 7, result)
+	}
+	if _, err := e.FirstOrErr(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+}
+
+func TestFirstShortCircuits(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4, 5}).Map(func(i int) int {
+		calls++
+		return i * 2
+	})
+	result, _ := e.First()
+
+	if result != 2 {
+		t.Errorf("Expected %d, got %d", 2, result)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestFirstWhere(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4, 5}).Map(func(i int) int {
+		calls++
+		return i
+	})
+	result, err := e.FirstWhereOrErr(func(i int) bool { return i > 2 })
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if result != 3 {
+		t.Errorf("Expected %d, got %d", 3, result)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+	if result := e.FirstWhereOrDefault(func(i int) bool { return i > 5 }, -1); result != -1 {
+		t.Errorf("Expected %d, got %d", -1, result)
+	}
+}
+
+func TestLast(t *testing.T) {
+	e := New([]int{1, 2, 3})
+
+	if result, _ := e.Last(); result != 3 {
+		t.Errorf("Expected %d, got %d", 3, result)
+	}
+	if result, _ := e.Filter(func(i int) bool { return i < 3 }).Last(); result != 2 {
+		t.Errorf("Expected %d, got %d", 2, result)
+	}
+	if _, err := e.Filter(func(i int) bool { return i > 3 }).LastOrErr(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+	if result := New([]int{}).LastOrDefault(7); result != 7 {
+		t.Errorf("Expected %d, got %d", 7, result)
+	}
+}
+
+func TestSingle(t *testing.T) {
+	e := New([]int{1, 2, 3})
+
+	if result, err := e.Filter(func(i int) bool { return i == 2 }).SingleOrErr(); err != nil || result != 2 {
+		t.Errorf("Expected %d, got %d (%v)", 2, result, err)
+	}
+	if _, err := e.SingleOrErr(); !errors.Is(err, ErrMultiple) {
+		t.Errorf("Expected %v, got %v", ErrMultiple, err)
+	}
+	if _, err := e.Filter(func(i int) bool { return i > 3 }).SingleOrErr(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+	if _, ok := e.Single(); ok {
+		t.Errorf("Expected false, got true")
+	}
+	if result := e.SingleOrDefault(7); result != 7 {
+		t.Errorf("Expected %d, got %d", 7, result)
+	}
+}
+
+func TestElementAt(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4, 5}).Map(func(i int) int {
+		calls++
+		return i
+	})
+	result, ok := e.ElementAt(2)
+
+	if !ok {
+		t.Errorf("Expected true, got false")
+	}
+	if result != 3 {
+		t.Errorf("Expected %d, got %d", 3, result)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestElementAtOutOfRange(t *testing.T) {
+	e := New([]int{1, 2, 3})
+
+	if _, ok := e.ElementAt(-1); ok {
+		t.Errorf("Expected false, got true")
+	}
+	if _, err := e.ElementAtOrErr(3); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Expected %v, got %v", ErrOutOfRange, err)
+	}
+	if result := e.ElementAtOrDefault(5, 7); result != 7 {
+		t.Errorf("Expected %d, got %d", 7, result)
+	}
+}
@@ -1,39 +1,56 @@
 package enumerable
 
-type Enumerable[T comparable] struct {
-	values []T
-	stack  []func(Enumerable[T]) Enumerable[T]
+import "slices"
+
+type Enumerable[T any] struct {
+	values   []T
+	source   func(*scope) iterator[T]
+	stack    []stage[T]
+	observer *observer
 }
 
 // Create a new Enumerable[T] from a slice of T
-func New[T comparable](values []T) Enumerable[T] {
-	return Enumerable[T]{values, []func(Enumerable[T]) Enumerable[T]{}}
+func New[T any](values []T) Enumerable[T] {
+	return Enumerable[T]{values: values, stack: []stage[T]{}}
 }
 
 // Append a value to the Enumerable[T] and return a new Enumerable[T]
 // Evaluates lazily, call apply to evaluate
 func (e Enumerable[T]) Append(value T) Enumerable[T] {
-	return e.lazy(func(e Enumerable[T]) Enumerable[T] {
-		e.values = append(e.values, value)
-		return e
+	return e.stream("Append", func(next iterator[T]) iterator[T] {
+		done := false
+		return func() (T, bool) {
+			if v, ok := next(); ok {
+				return v, true
+			}
+			if done {
+				var zero T
+				return zero, false
+			}
+			done = true
+			return value, true
+		}
 	})
 }
 
 // Map a function over the Enumerable[T], returning a new Enumerable[T]
 // Evaluates lazily, call apply to evaluate
 func (e Enumerable[T]) Map(f func(T) T) Enumerable[T] {
-	return e.lazy(func(e Enumerable[T]) Enumerable[T] {
-		for i, v := range e.values {
-			e.values[i] = f(v)
+	return e.stream(OpMap, func(next iterator[T]) iterator[T] {
+		return func() (T, bool) {
+			v, ok := next()
+			if ok {
+				v = f(v)
+			}
+			return v, ok
 		}
-		return e
-	})
+	}).describe(func(s *stage[T]) { s.mapf = f })
 }
 
 // Reverse the order of the Enumerable[T]
 // Evaluates lazily, call apply to evaluate
 func (e Enumerable[T]) Reverse() Enumerable[T] {
-	return e.lazy(func(e Enumerable[T]) Enumerable[T] {
+	return e.lazy(OpReverse, func(e Enumerable[T]) Enumerable[T] {
 		result := New([]T{})
 		for i := len(e.values) - 1; i >= 0; i-- {
 			result.values = append(result.values, e.values[i])
@@ -45,17 +62,16 @@ func (e Enumerable[T]) Reverse() Enumerable[T] {
 // Filter an Enumerable[T] by a predicate function
 // Evaluates lazily, call apply to evaluate
 func (e Enumerable[T]) Filter(f func(T) bool) Enumerable[T] {
-	return e.lazy(func(e Enumerable[T]) Enumerable[T] {
-		index := 0
-		for _, v := range e.values {
-			if f(v) {
-				e.values = append(e.values[:index], v)
-				index++
+	return e.stream(OpFilter, func(next iterator[T]) iterator[T] {
+		return func() (T, bool) {
+			for {
+				v, ok := next()
+				if !ok || f(v) {
+					return v, ok
+				}
 			}
 		}
-		e.values = e.values[:index]
-		return e
-	})
+	}).describe(func(s *stage[T]) { s.pred = f })
 }
 
 // Take the first n values of the Enumerable[T]
@@ -63,151 +79,305 @@ func (e Enumerable[T]) Filter(f func(T) bool) Enumerable[T] {
 // If n is negative, returns the last n values of the Enumerable[T]
 // Evaluates lazily, call apply to evaluate
 func (e Enumerable[T]) Take(n int) Enumerable[T] {
-	return e.lazy(func(Enumerable[T]) Enumerable[T] {
-		reversed := false
-		if n < 0 {
-			e = e.Reverse().Apply()
-			n = -n
-			reversed = true
-		}
-		if n > len(e.values) {
-			n = len(e.values)
+	if n < 0 {
+		return e.TakeLast(-n)
+	}
+	return e.stream(OpTake, func(next iterator[T]) iterator[T] {
+		taken := 0
+		return func() (T, bool) {
+			if taken >= n {
+				var zero T
+				return zero, false
+			}
+			taken++
+			return next()
 		}
-		e.values = e.values[:n]
-		if reversed {
-			e = e.Reverse().Apply()
+	}).describe(func(s *stage[T]) { s.n = n })
+}
+
+// Take the first values of the Enumerable[T] that satisfy a predicate function
+// Stops at the first value that does not satisfy the predicate
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) TakeWhile(f func(T) bool) Enumerable[T] {
+	return e.stream("TakeWhile", func(next iterator[T]) iterator[T] {
+		done := false
+		return func() (T, bool) {
+			if !done {
+				if v, ok := next(); ok && f(v) {
+					return v, true
+				}
+				done = true
+			}
+			var zero T
+			return zero, false
 		}
-		return e
 	})
 }
 
-// Take the first n values of the Enumerable[T] that satisfy a predicate function
+// Take the values of the Enumerable[T] up to and including the first value that satisfies a predicate function
 // Evaluates lazily, call apply to evaluate
-func (e Enumerable[T]) TakeWhile(f func(T) bool) Enumerable[T] {
-	return e.lazy(func(Enumerable[T]) Enumerable[T] {
-		index := 0
-		for i, v := range e.values {
-			if !f(v) {
-				index = i
-				break
+func (e Enumerable[T]) TakeUntil(f func(T) bool) Enumerable[T] {
+	return e.stream("TakeUntil", func(next iterator[T]) iterator[T] {
+		done := false
+		return func() (T, bool) {
+			if done {
+				var zero T
+				return zero, false
 			}
+			v, ok := next()
+			done = !ok || f(v)
+			return v, ok
 		}
-		e.values = e.values[:index]
-		return e
 	})
 }
 
+// Take the last n values of the Enumerable[T]
+// If n is greater than the length of the Enumerable[T], returns the Enumerable[T]
+// Holds at most n values in memory while evaluating
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) TakeLast(n int) Enumerable[T] {
+	return e.stream(OpTakeLast, func(next iterator[T]) iterator[T] {
+		var buffer *ring[T]
+		return func() (T, bool) {
+			if buffer == nil {
+				buffer = newRing[T](n)
+				for v, ok := next(); ok; v, ok = next() {
+					buffer.push(v)
+				}
+			}
+			return buffer.shift()
+		}
+	}).describe(func(s *stage[T]) { s.n = n })
+}
+
 // Skip the first n values of the Enumerable[T]
 // If n is greater than the length of the Enumerable[T], returns an empty Enumerable[T]
 // If n is negative, returns all but the last n values of the Enumerable[T]
 // Evaluates lazily, call apply to evaluate
 func (e Enumerable[T]) Skip(n int) Enumerable[T] {
-	return e.lazy(func(Enumerable[T]) Enumerable[T] {
-		reversed := false
-		if n < 0 {
-			e = e.Reverse().Apply()
-			n = -n
-			reversed = true
-		}
-		if n > len(e.values) {
-			n = len(e.values)
-		}
-		e.values = e.values[n:]
-		if reversed {
-			e = e.Reverse().Apply()
+	if n < 0 {
+		return e.SkipLast(-n)
+	}
+	return e.stream(OpSkip, func(next iterator[T]) iterator[T] {
+		skipped := 0
+		return func() (T, bool) {
+			for ; skipped < n; skipped++ {
+				if _, ok := next(); !ok {
+					var zero T
+					return zero, false
+				}
+			}
+			return next()
 		}
-		return e
-	})
+	}).describe(func(s *stage[T]) { s.n = n })
 }
 
 // Skip the first values of the Enumerable[T] that satisfy a predicate function
+// Returns every value from the first value that does not satisfy the predicate
 // Evaluates lazily, call apply to evaluate
 func (e Enumerable[T]) SkipWhile(f func(T) bool) Enumerable[T] {
-	return e.lazy(func(Enumerable[T]) Enumerable[T] {
-		index := 0
-		for i, v := range e.values {
-			if !f(v) {
-				index = i
-				break
+	return e.stream("SkipWhile", func(next iterator[T]) iterator[T] {
+		skipping := true
+		return func() (T, bool) {
+			for {
+				v, ok := next()
+				if !ok || !skipping || !f(v) {
+					skipping = false
+					return v, ok
+				}
 			}
 		}
-		e.values = e.values[index:]
-		return e
 	})
 }
 
-// Contains returns true if the Enumerable[T] contains the value
-func (e Enumerable[T]) Contains(value T) bool {
-	for _, v := range e.Apply().values {
-		if v == value {
-			return true
+// Skip the values of the Enumerable[T] before the first value that satisfies a predicate function
+// Returns every value from the first value that satisfies the predicate
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) SkipUntil(f func(T) bool) Enumerable[T] {
+	return e.SkipWhile(func(v T) bool { return !f(v) })
+}
+
+// Skip the last n values of the Enumerable[T]
+// If n is greater than the length of the Enumerable[T], returns an empty Enumerable[T]
+// Values are returned as soon as n later values have been seen, holding at most n values in memory
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) SkipLast(n int) Enumerable[T] {
+	return e.stream(OpSkipLast, func(next iterator[T]) iterator[T] {
+		buffer := newRing[T](n)
+		return func() (T, bool) {
+			for v, ok := next(); ok; v, ok = next() {
+				if n <= 0 {
+					return v, true
+				}
+				if buffer.full() {
+					oldest, _ := buffer.shift()
+					buffer.push(v)
+					return oldest, true
+				}
+				buffer.push(v)
+			}
+			var zero T
+			return zero, false
 		}
-	}
-	return false
+	}).describe(func(s *stage[T]) { s.n = n })
+}
+
+// Contains returns true if the Enumerable[T] contains the value
+// Stops evaluating at the first match
+func Contains[T comparable](e Enumerable[T], value T) bool {
+	return e.Any(func(v T) bool { return v == value })
 }
 
 // Any returns true if the Enumerable[T] contains a value that satisfies the predicate
+// Stops evaluating at the first value that satisfies the predicate
 func (e Enumerable[T]) Any(f func(T) bool) bool {
-	for _, v := range e.Apply().values {
-		if f(v) {
-			return true
-		}
-	}
-	return false
+	_, found := e.FirstWhere(f)
+	return found
 }
 
 // All returns true if all values in the Enumerable[T] satisfy the predicate
+// Stops evaluating at the first value that does not satisfy the predicate
 func (e Enumerable[T]) All(f func(T) bool) bool {
-	result := true
-	for _, v := range e.Apply().values {
-		if !f(v) {
-			result = false
-		}
-	}
-	return result
+	return !e.Any(func(v T) bool { return !f(v) })
 }
 
 // Reduce the Enumerable[T] to a single value
-func (e Enumerable[T]) Reduce(f func(T, T) T) T {
-	e = e.Apply()
-	result := e.values[0]
-	for _, v := range e.values[1:] {
+// Returns false if the Enumerable[T] is empty
+func (e Enumerable[T]) Reduce(f func(T, T) T) (T, bool) {
+	s := newScope()
+	defer s.close()
+	next := e.run().iterate(s)
+	result, ok := next()
+	if !ok {
+		return result, false
+	}
+	for v, ok := next(); ok; v, ok = next() {
 		result = f(result, v)
 	}
-	return result
+	return result, true
 }
 
 // Iterate over the Enumerable[T], calling the function for each value
+// Panics if a source fails, use ForEachErr for sources that can fail
 func (e Enumerable[T]) ForEach(f func(T)) {
-	for _, v := range e.Apply().values {
+	s := newScope()
+	defer s.close()
+	next := e.run().iterate(s)
+	for v, ok := next(); ok; v, ok = next() {
 		f(v)
 	}
 }
 
+// Iterate over the Enumerable[T], calling the function for each value
+// Returns the error of a source that failed, such as an external sort
+func (e Enumerable[T]) ForEachErr(f func(T)) (err error) {
+	defer recoverErr(&err)
+	e.ForEach(f)
+	return nil
+}
+
 // Map a function over the Enumerable[T] but return a new Enumerable of a different type
-func Transform[T comparable, U comparable](e Enumerable[T], f func(T) U) Enumerable[U] {
+func Transform[T any, U any](e Enumerable[T], f func(T) U) Enumerable[U] {
 	result := New([]U{})
 	for _, v := range e.Apply().values {
 		// TODO: Lazy evaluation broken here
 		result = result.Append(f(v))
 	}
-	return result.Apply()
+	result = result.Apply()
+	result.observer = e.observer
+	return result
+}
+
+// TransformErr maps a function that can fail over the Enumerable[T], returning an Enumerable of a different type
+// The first error returned by f stops the evaluation and is returned by ToListErr and ForEachErr
+// Evaluates lazily, call apply to evaluate
+func TransformErr[T any, U any](e Enumerable[T], f func(T) (U, error)) Enumerable[U] {
+	result := fromSource(func(s *scope) iterator[U] {
+		next := e.run().iterate(s)
+		return func() (U, bool) {
+			var zero U
+			v, ok := next()
+			if !ok || s.err != nil {
+				return zero, false
+			}
+			u, err := f(v)
+			if err != nil {
+				s.fail(err)
+				return zero, false
+			}
+			return u, true
+		}
+	})
+	result.observer = e.observer
+	return result
 }
 
 // Apply all the functions from the stack to the Enumerable[T]
+// Every terminal operation evaluates the stack again, use Memoize to evaluate it once
 func (e Enumerable[T]) Apply() Enumerable[T] {
-	for _, f := range e.stack {
-		e = f(e)
-	}
-	return e
+	result := e.run().collect()
+	result.observer = e.observer
+	return result
 }
 
 // Apply any pending operations and return the values as a slice
+// Panics if a source fails, use ToListErr for sources that can fail
 func (e Enumerable[T]) ToList() []T {
 	return e.Apply().values
 }
 
-func (e Enumerable[T]) lazy(f func(Enumerable[T]) Enumerable[T]) Enumerable[T] {
-	e.stack = append(e.stack, f)
+// Apply any pending operations and return the values as a slice
+// Returns the error of a source that failed, such as an external sort
+func (e Enumerable[T]) ToListErr() (values []T, err error) {
+	defer recoverErr(&err)
+	return e.ToList(), nil
+}
+
+// lazy adds a function that needs all of the values of the Enumerable[T] to the stack
+func (e Enumerable[T]) lazy(op Op, f func(Enumerable[T]) Enumerable[T]) Enumerable[T] {
+	return e.push(stage[T]{op: op, apply: func(e Enumerable[T]) Enumerable[T] {
+		return f(e.collect())
+	}})
+}
+
+// stream adds a function that wraps the iterator of the Enumerable[T] to the stack
+// Values are pulled through the wrapped iterator one at a time instead of being materialized
+func (e Enumerable[T]) stream(op Op, f func(iterator[T]) iterator[T]) Enumerable[T] {
+	return e.streamScoped(op, func(_ *scope, next iterator[T]) iterator[T] {
+		return f(next)
+	})
+}
+
+// streamScoped is stream for iterators that hold resources or can fail
+func (e Enumerable[T]) streamScoped(op Op, f func(*scope, iterator[T]) iterator[T]) Enumerable[T] {
+	return e.push(stage[T]{op: op, apply: func(e Enumerable[T]) Enumerable[T] {
+		return fromSource(func(s *scope) iterator[T] {
+			return f(s, e.iterate(s))
+		})
+	}})
+}
+
+// push adds a stage to a copy of the stack so Enumerables built from the same parent do not share stages
+func (e Enumerable[T]) push(s stage[T]) Enumerable[T] {
+	e.stack = append(slices.Clip(e.stack), s)
+	return e
+}
+
+// describe records the arguments of the last stage on the stack for the optimizer
+func (e Enumerable[T]) describe(f func(*stage[T])) Enumerable[T] {
+	f(&e.stack[len(e.stack)-1])
+	return e
+}
+
+// run applies the functions from the stack without draining the resulting source
+func (e Enumerable[T]) run() Enumerable[T] {
+	if e.observer != nil {
+		return e.observed()
+	}
+	stack := e.stack
+	e.stack = nil
+	for _, s := range stack {
+		e = s.apply(e)
+	}
 	return e
 }
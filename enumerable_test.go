@@ -1,6 +1,7 @@
 package enumerable
 
 import (
+	"reflect"
 	"strconv"
 	"testing"
 )
@@ -52,9 +53,12 @@ func TestMapString(t *testing.T) {
 
 func TestReduceInt(t *testing.T) {
 	e := New([]int{1, 2, 3})
-	result := e.Reduce(func(a, b int) int { return a + b })
+	result, ok := e.Reduce(func(a, b int) int { return a + b })
 	expected := 6
 
+	if !ok {
+		t.Errorf("Expected true, got false")
+	}
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
@@ -62,14 +66,26 @@ func TestReduceInt(t *testing.T) {
 
 func TestReduceString(t *testing.T) {
 	e := New([]string{"a", "b", "c"})
-	result := e.Reduce(func(a, b string) string { return a + b })
+	result, ok := e.Reduce(func(a, b string) string { return a + b })
 	expected := "abc"
 
+	if !ok {
+		t.Errorf("Expected true, got false")
+	}
 	if result != expected {
 		t.Errorf("Expected %s, got %s", expected, result)
 	}
 }
 
+func TestReduceEmpty(t *testing.T) {
+	e := New([]int{})
+	_, ok := e.Reduce(func(a, b int) int { return a + b })
+
+	if ok {
+		t.Errorf("Expected false, got true")
+	}
+}
+
 func TestForEach(t *testing.T) {
 	e := New([]int{1, 2, 3})
 	var result int
@@ -204,10 +220,10 @@ func TestReverseThenMap(t *testing.T) {
 func TestContains(t *testing.T) {
 	e := New([]int{1, 2, 3})
 
-	if !e.Contains(1) {
+	if !Contains(e, 1) {
 		t.Errorf("Expected true, got false")
 	}
-	if e.Contains(4) {
+	if Contains(e, 4) {
 		t.Errorf("Expected false, got true")
 	}
 }
@@ -225,7 +241,7 @@ func TestAny(t *testing.T) {
 
 func TestFilterThenReduce(t *testing.T) {
 	e := New([]int{1, 2, 3})
-	result := e.Filter(func(i int) bool { return i > 1 }).Reduce(func(a int, b int) int { return a + b })
+	result, _ := e.Filter(func(i int) bool { return i > 1 }).Reduce(func(a int, b int) int { return a + b })
 	expected := 5
 
 	if result != expected {
@@ -247,10 +263,10 @@ func TestFilterThenForEach(t *testing.T) {
 func TestFilterThenContains(t *testing.T) {
 	e := New([]int{1, 2, 3})
 
-	if !e.Filter(func(i int) bool { return i > 1 }).Contains(2) {
+	if !Contains(e.Filter(func(i int) bool { return i > 1 }), 2) {
 		t.Errorf("Expected true, got false")
 	}
-	if e.Filter(func(i int) bool { return i > 1 }).Contains(1) {
+	if Contains(e.Filter(func(i int) bool { return i > 1 }), 1) {
 		t.Errorf("Expected false, got true")
 	}
 }
@@ -441,3 +457,186 @@ func TestSkipWhileFalse(t *testing.T) {
 		}
 	}
 }
+
+func TestPredicatePrefixOperators(t *testing.T) {
+	values := []int{1, 2, 3, 1}
+	allTrue := func(i int) bool { return true }
+	allFalse := func(i int) bool { return false }
+	lessThan3 := func(i int) bool { return i < 3 }
+	tests := []struct {
+		name     string
+		op       func(Enumerable[int]) Enumerable[int]
+		values   []int
+		expected []int
+	}{
+		{"TakeWhile all true", func(e Enumerable[int]) Enumerable[int] { return e.TakeWhile(allTrue) }, values, []int{1, 2, 3, 1}},
+		{"TakeWhile all false", func(e Enumerable[int]) Enumerable[int] { return e.TakeWhile(allFalse) }, values, []int{}},
+		{"TakeWhile mixed", func(e Enumerable[int]) Enumerable[int] { return e.TakeWhile(lessThan3) }, values, []int{1, 2}},
+		{"TakeWhile empty", func(e Enumerable[int]) Enumerable[int] { return e.TakeWhile(allTrue) }, []int{}, []int{}},
+		{"SkipWhile all true", func(e Enumerable[int]) Enumerable[int] { return e.SkipWhile(allTrue) }, values, []int{}},
+		{"SkipWhile all false", func(e Enumerable[int]) Enumerable[int] { return e.SkipWhile(allFalse) }, values, []int{1, 2, 3, 1}},
+		{"SkipWhile mixed", func(e Enumerable[int]) Enumerable[int] { return e.SkipWhile(lessThan3) }, values, []int{3, 1}},
+		{"SkipWhile empty", func(e Enumerable[int]) Enumerable[int] { return e.SkipWhile(allTrue) }, []int{}, []int{}},
+		{"TakeUntil all true", func(e Enumerable[int]) Enumerable[int] { return e.TakeUntil(allTrue) }, values, []int{1}},
+		{"TakeUntil all false", func(e Enumerable[int]) Enumerable[int] { return e.TakeUntil(allFalse) }, values, []int{1, 2, 3, 1}},
+		{"TakeUntil mixed", func(e Enumerable[int]) Enumerable[int] { return e.TakeUntil(func(i int) bool { return i == 2 }) }, values, []int{1, 2}},
+		{"TakeUntil empty", func(e Enumerable[int]) Enumerable[int] { return e.TakeUntil(allTrue) }, []int{}, []int{}},
+		{"SkipUntil all true", func(e Enumerable[int]) Enumerable[int] { return e.SkipUntil(allTrue) }, values, []int{1, 2, 3, 1}},
+		{"SkipUntil all false", func(e Enumerable[int]) Enumerable[int] { return e.SkipUntil(allFalse) }, values, []int{}},
+		{"SkipUntil mixed", func(e Enumerable[int]) Enumerable[int] { return e.SkipUntil(func(i int) bool { return i == 2 }) }, values, []int{2, 3, 1}},
+		{"SkipUntil empty", func(e Enumerable[int]) Enumerable[int] { return e.SkipUntil(allTrue) }, []int{}, []int{}},
+	}
+	for _, test := range tests {
+		result := test.op(New(test.values)).ToList()
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, result)
+		}
+	}
+}
+
+func TestCountOperators(t *testing.T) {
+	values := []int{1, 2, 3}
+	tests := []struct {
+		n        int
+		take     []int
+		skip     []int
+		takeLast []int
+		skipLast []int
+	}{
+		{-4, []int{1, 2, 3}, []int{}, []int{}, []int{1, 2, 3}},
+		{-3, []int{1, 2, 3}, []int{}, []int{}, []int{1, 2, 3}},
+		{-2, []int{2, 3}, []int{1}, []int{}, []int{1, 2, 3}},
+		{-1, []int{3}, []int{1, 2}, []int{}, []int{1, 2, 3}},
+		{0, []int{}, []int{1, 2, 3}, []int{}, []int{1, 2, 3}},
+		{1, []int{1}, []int{2, 3}, []int{3}, []int{1, 2}},
+		{2, []int{1, 2}, []int{3}, []int{2, 3}, []int{1}},
+		{3, []int{1, 2, 3}, []int{}, []int{1, 2, 3}, []int{}},
+		{4, []int{1, 2, 3}, []int{}, []int{1, 2, 3}, []int{}},
+	}
+	for _, test := range tests {
+		e := New(values)
+		if result := e.Take(test.n).ToList(); !reflect.DeepEqual(result, test.take) {
+			t.Errorf("Take %d: expected %v, got %v", test.n, test.take, result)
+		}
+		if result := e.Skip(test.n).ToList(); !reflect.DeepEqual(result, test.skip) {
+			t.Errorf("Skip %d: expected %v, got %v", test.n, test.skip, result)
+		}
+		if result := e.TakeLast(test.n).ToList(); !reflect.DeepEqual(result, test.takeLast) {
+			t.Errorf("TakeLast %d: expected %v, got %v", test.n, test.takeLast, result)
+		}
+		if result := e.SkipLast(test.n).ToList(); !reflect.DeepEqual(result, test.skipLast) {
+			t.Errorf("SkipLast %d: expected %v, got %v", test.n, test.skipLast, result)
+		}
+	}
+}
+
+func TestTakeWhileShortCircuits(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4}).Map(func(i int) int {
+		calls++
+		return i
+	})
+	e.TakeWhile(func(i int) bool { return i < 2 }).ToList()
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestSkipLastStreams(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4, 5}).Map(func(i int) int {
+		calls++
+		return i
+	})
+	result, _ := e.SkipLast(2).First()
+
+	if result != 1 {
+		t.Errorf("Expected %d, got %d", 1, result)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestTakeLastAfterFilter(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5, 6})
+	result := e.Filter(func(i int) bool { return i%2 == 0 }).TakeLast(2).ToList()
+	expected := []int{4, 6}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestAllShortCircuits(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4}).Map(func(i int) int {
+		calls++
+		return i
+	})
+
+	if e.All(func(i int) bool { return i < 2 }) {
+		t.Errorf("Expected false, got true")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestAnyShortCircuits(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4}).Map(func(i int) int {
+		calls++
+		return i
+	})
+
+	if !e.Any(func(i int) bool { return i == 2 }) {
+		t.Errorf("Expected true, got false")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestContainsShortCircuits(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4}).Map(func(i int) int {
+		calls++
+		return i
+	})
+
+	if !Contains(e, 1) {
+		t.Errorf("Expected true, got false")
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestAllEmpty(t *testing.T) {
+	e := New([]int{})
+
+	if !e.All(func(i int) bool { return false }) {
+		t.Errorf("Expected true, got false")
+	}
+}
+
+func TestTransformErr(t *testing.T) {
+	calls := 0
+	e := TransformErr(New([]string{"1", "2", "x", "4"}), func(s string) (int, error) {
+		calls++
+		return strconv.Atoi(s)
+	})
+	values, err := e.Take(2).ToListErr()
+
+	if !reflect.DeepEqual(values, []int{1, 2}) || err != nil {
+		t.Errorf("Expected [1 2], got %v, %v", values, err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+	if _, err := e.ToListErr(); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
@@ -0,0 +1,14 @@
+package enumerable
+
+import "errors"
+
+var (
+	// ErrEmpty is returned when an operation requires a value but the Enumerable has none
+	ErrEmpty = errors.New("enumerable: no values")
+	// ErrMultiple is returned when an operation requires exactly one value but the Enumerable has more
+	ErrMultiple = errors.New("enumerable: more than one value")
+	// ErrOutOfRange is returned when an index is outside of the Enumerable
+	ErrOutOfRange = errors.New("enumerable: index out of range")
+	// ErrConsumed is returned when evaluating an Enumerable whose source can only be read once a second time
+	ErrConsumed = errors.New("enumerable: source has already been read")
+)
@@ -0,0 +1,51 @@
+package enumerable
+
+import "github.com/sdehm/go-enumerable/expr"
+
+// FilterExpr filters an Enumerable[T] by a boolean expression, which unlike the func given to Filter can be
+// printed, serialized and translated
+// f is type checked against T, type errors and errors evaluating f stop the evaluation and are returned by
+// ToListErr and ForEachErr
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) FilterExpr(f expr.Expr) Enumerable[T] {
+	match, err := expr.Predicate[T](f)
+	return e.streamScoped("FilterExpr", func(s *scope, next iterator[T]) iterator[T] {
+		if err != nil {
+			s.fail(err)
+			return emptyIterator[T]
+		}
+		return func() (T, bool) {
+			for {
+				v, ok := next()
+				if !ok {
+					return v, false
+				}
+				keep, err := match(v)
+				if err != nil {
+					s.fail(err)
+					var zero T
+					return zero, false
+				}
+				if keep {
+					return v, true
+				}
+			}
+		}
+	})
+}
+
+// MapExpr maps the expression f over the Enumerable[T], which unlike the func given to Map can be
+// printed, serialized and translated
+// f is type checked against T, type errors and errors evaluating f stop the evaluation and are returned by
+// ToListErr and ForEachErr
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) MapExpr(f expr.Expr) Enumerable[any] {
+	eval, err := expr.Compile[T](f)
+	if err != nil {
+		return fromSource(func(s *scope) iterator[any] {
+			s.fail(err)
+			return emptyIterator[any]
+		})
+	}
+	return TransformErr(e, eval)
+}
@@ -0,0 +1,229 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrType is returned when an expression is not valid for the values it is evaluated against
+var ErrType = errors.New("expr: type error")
+
+var (
+	boolType = reflect.TypeFor[bool]()
+	timeType = reflect.TypeFor[time.Time]()
+)
+
+// category groups the types that can be compared with each other
+type category int
+
+const (
+	other category = iota
+	numeric
+	text
+	boolean
+	instant
+)
+
+func categoryOf(t reflect.Type) category {
+	switch {
+	case t == timeType:
+		return instant
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		return numeric
+	case t.Kind() == reflect.String:
+		return text
+	case t.Kind() == reflect.Bool:
+		return boolean
+	default:
+		return other
+	}
+}
+
+// Check type checks e against values of type t and returns the type of its result
+// The result is nil when the type is only known during evaluation, such as a field of a map[string]any
+func Check(e Expr, t reflect.Type) (reflect.Type, error) {
+	switch e.Op {
+	case OpField:
+		_, result, err := compileField(t, e.Name)
+		return result, err
+	case OpConst:
+		if e.Value == nil {
+			return nil, nil
+		}
+		return deref(reflect.TypeOf(e.Value)), nil
+	case OpEq, OpNe, OpLt, OpLe, OpGt, OpGe:
+		if err := arity(e, 2); err != nil {
+			return nil, err
+		}
+		return boolType, checkComparison(e, t, e.Args[1])
+	case OpIn:
+		if len(e.Args) == 0 {
+			return nil, arity(e, 1)
+		}
+		for _, a := range e.Args[1:] {
+			if err := checkComparison(e, t, a); err != nil {
+				return nil, err
+			}
+		}
+		return boolType, nil
+	case OpAnd, OpOr, OpNot:
+		if len(e.Args) == 0 || e.Op == OpNot {
+			if err := arity(e, 1); err != nil {
+				return nil, err
+			}
+		}
+		return boolType, checkArgs(e, t, boolean)
+	case OpPrefix, OpSuffix, OpContains:
+		if err := arity(e, 2); err != nil {
+			return nil, err
+		}
+		return boolType, checkArgs(e, t, text)
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrType, e.Op)
+	}
+}
+
+// checkComparison checks that the first argument of e can be compared with operand
+func checkComparison(e Expr, t reflect.Type, operand Expr) error {
+	left, err := Check(e.Args[0], t)
+	if err != nil {
+		return err
+	}
+	right, err := Check(operand, t)
+	if err != nil || left == nil || right == nil {
+		return err
+	}
+	c := categoryOf(left)
+	if c != categoryOf(right) || c == other && left != right {
+		return fmt.Errorf("%w: cannot compare %v and %v in %v", ErrType, left, right, e)
+	}
+	ordered := e.Op != OpEq && e.Op != OpNe && e.Op != OpIn
+	if ordered && c != numeric && c != text && c != instant {
+		return fmt.Errorf("%w: cannot order %v in %v", ErrType, left, e)
+	}
+	return nil
+}
+
+// checkArgs checks that every argument of e is in category c
+func checkArgs(e Expr, t reflect.Type, c category) error {
+	for _, a := range e.Args {
+		result, err := Check(a, t)
+		if err != nil {
+			return err
+		}
+		if result != nil && categoryOf(result) != c {
+			return fmt.Errorf("%w: %v is %v in %v", ErrType, a, result, e)
+		}
+	}
+	return nil
+}
+
+func arity(e Expr, n int) error {
+	if len(e.Args) != n {
+		return fmt.Errorf("%w: %s needs %d arguments, got %d", ErrType, e.Op, n, len(e.Args))
+	}
+	return nil
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// getter returns a field of a value, or the invalid Value when the field is null
+type getter func(reflect.Value) (reflect.Value, error)
+
+// compileField returns a getter for the dotted field name of values of type t and the type of the field
+// The empty name is the value itself
+// Parts of the path whose type is only known during evaluation are looked up by name each time
+func compileField(t reflect.Type, name string) (getter, reflect.Type, error) {
+	var steps []getter
+	var parts []string
+	if name != "" {
+		parts = strings.Split(name, ".")
+	}
+	for _, part := range parts {
+		t = deref(t)
+		switch {
+		case t == nil || t.Kind() == reflect.Interface:
+			t = nil
+			steps = append(steps, func(v reflect.Value) (reflect.Value, error) {
+				return lookup(v, part)
+			})
+		case t.Kind() == reflect.Struct:
+			f, ok := t.FieldByName(part)
+			if !ok || !f.IsExported() {
+				return nil, nil, fmt.Errorf("%w: %v has no field %q", ErrType, t, part)
+			}
+			t = f.Type
+			steps = append(steps, func(v reflect.Value) (reflect.Value, error) {
+				return fieldByIndex(v, f.Index), nil
+			})
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			key := reflect.ValueOf(part).Convert(t.Key())
+			t = t.Elem()
+			steps = append(steps, func(v reflect.Value) (reflect.Value, error) {
+				return v.MapIndex(key), nil
+			})
+		default:
+			return nil, nil, fmt.Errorf("%w: %v has no field %q", ErrType, t, part)
+		}
+	}
+	if t != nil && t.Kind() == reflect.Interface {
+		t = nil
+	}
+	get := func(v reflect.Value) (reflect.Value, error) {
+		for _, step := range steps {
+			if v = indirect(v); !v.IsValid() {
+				return v, nil
+			}
+			var err error
+			if v, err = step(v); err != nil {
+				return v, err
+			}
+		}
+		return indirect(v), nil
+	}
+	return get, deref(t), nil
+}
+
+// lookup returns the field or map key name of v whose type is only known during evaluation
+func lookup(v reflect.Value, name string) (reflect.Value, error) {
+	switch {
+	case v.Kind() == reflect.Struct:
+		f, ok := v.Type().FieldByName(name)
+		if !ok || !f.IsExported() {
+			return v, fmt.Errorf("%w: %v has no field %q", ErrType, v.Type(), name)
+		}
+		return fieldByIndex(v, f.Index), nil
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		return v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())), nil
+	default:
+		return v, fmt.Errorf("%w: %v has no field %q", ErrType, v.Type(), name)
+	}
+}
+
+// fieldByIndex returns the field of the struct v at index, or the invalid Value when it is promoted through a nil pointer
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	f, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return f
+}
+
+// indirect follows pointers and interfaces, returning the invalid Value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
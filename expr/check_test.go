@@ -0,0 +1,53 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		expr     Expr
+		expected reflect.Type
+	}{
+		{Field("Age"), reflect.TypeFor[int]()},
+		{Field("Nick"), reflect.TypeFor[string]()},
+		{Field("City"), reflect.TypeFor[string]()},
+		{Field("Tags.any"), nil},
+		{Field("Tags.any.deeper"), nil},
+		{Const(nil), nil},
+		{Field("Age").Gt(1.5), reflect.TypeFor[bool]()},
+		{Field("Tags.x").Gt("a"), reflect.TypeFor[bool]()},
+		{Field("Name").Eq(nil), reflect.TypeFor[bool]()},
+	}
+	for _, tt := range tests {
+		result, err := Check(tt.expr, reflect.TypeFor[Person]())
+		if err != nil || result != tt.expected {
+			t.Errorf("Expected %v for %v, got %v, %v", tt.expected, tt.expr, result, err)
+		}
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	tests := []Expr{
+		Field("Missing"),
+		Field("private"),
+		Field("Age.Years"),
+		Field("Name").Gt(1),
+		Field("Tags").Eq(1),
+		Field("Age").Eq(true).Not(),
+		Field("Age").And(Field("Name").Eq("a")),
+		Field("Age").HasPrefix("1"),
+		Field("Name").In("a", 1),
+		Field("Name").Gt(Field("Born")),
+		{Op: OpEq, Args: []Expr{Field("Age")}},
+		{Op: OpNot},
+		{Op: "xor"},
+	}
+	for _, e := range tests {
+		if _, err := Check(e, reflect.TypeFor[Person]()); !errors.Is(err, ErrType) {
+			t.Errorf("Expected %v for %v, got %v", ErrType, e, err)
+		}
+	}
+}
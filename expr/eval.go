@@ -0,0 +1,344 @@
+package expr
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Func is an expression compiled for values of type T
+type Func[T any] func(T) (any, error)
+
+// evaluator evaluates a compiled expression, null values are nil
+type evaluator func(reflect.Value) (any, error)
+
+// Compile type checks e against T and returns a function evaluating it
+// Fields of structs are resolved once, only fields whose type is known during evaluation are looked up by name
+func Compile[T any](e Expr) (Func[T], error) {
+	t := reflect.TypeFor[T]()
+	if _, err := Check(e, t); err != nil {
+		return nil, err
+	}
+	eval := compile(e, t)
+	return func(v T) (any, error) {
+		return eval(reflect.ValueOf(&v).Elem())
+	}, nil
+}
+
+// Predicate compiles the boolean expression e for values of type T
+// Null values are false
+func Predicate[T any](e Expr) (func(T) (bool, error), error) {
+	t := reflect.TypeFor[T]()
+	result, err := Check(e, t)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil && result != boolType {
+		return nil, fmt.Errorf("%w: %v is %v, not bool", ErrType, e, result)
+	}
+	eval := compile(e, t)
+	return func(v T) (bool, error) {
+		result, err := eval(reflect.ValueOf(&v).Elem())
+		if err != nil {
+			return false, err
+		}
+		return asBool(e, result)
+	}, nil
+}
+
+// Eval evaluates e against v, type checking during evaluation
+func Eval(e Expr, v any) (any, error) {
+	if _, err := Check(e, nil); err != nil {
+		return nil, err
+	}
+	return compile(e, nil)(reflect.ValueOf(v))
+}
+
+// compile returns an evaluator for e, which has been type checked against t
+func compile(e Expr, t reflect.Type) evaluator {
+	switch e.Op {
+	case OpField:
+		get, _, _ := compileField(t, e.Name)
+		return func(v reflect.Value) (any, error) {
+			field, err := get(v)
+			if err != nil || !field.IsValid() {
+				return nil, err
+			}
+			return field.Interface(), nil
+		}
+	case OpConst:
+		value := indirect(reflect.ValueOf(e.Value))
+		return func(reflect.Value) (any, error) {
+			if !value.IsValid() {
+				return nil, nil
+			}
+			return value.Interface(), nil
+		}
+	case OpAnd, OpOr:
+		args := compileArgs(e, t)
+		// and stops at the first false operand, or at the first true one
+		stop := e.Op == OpOr
+		return func(v reflect.Value) (any, error) {
+			unknown := false
+			for i, arg := range args {
+				result, err := arg(v)
+				if err != nil {
+					return nil, err
+				}
+				if result == nil {
+					unknown = true
+					continue
+				}
+				b, err := asBool(e.Args[i], result)
+				if err != nil || b == stop {
+					return b, err
+				}
+			}
+			if unknown {
+				return nil, nil
+			}
+			return !stop, nil
+		}
+	case OpNot:
+		arg := compile(e.Args[0], t)
+		return func(v reflect.Value) (any, error) {
+			result, err := arg(v)
+			if err != nil || result == nil {
+				return nil, err
+			}
+			b, err := asBool(e.Args[0], result)
+			return !b, err
+		}
+	case OpIn:
+		args := compileArgs(e, t)
+		return func(v reflect.Value) (any, error) {
+			values, err := evalArgs(args, v)
+			if err != nil {
+				return nil, err
+			}
+			unknown := false
+			for _, value := range values[1:] {
+				if values[0] == nil || value == nil {
+					unknown = true
+					continue
+				}
+				if eq, err := equal(e, values[0], value); err != nil || eq {
+					return eq, err
+				}
+			}
+			if unknown {
+				return nil, nil
+			}
+			return false, nil
+		}
+	case OpPrefix, OpSuffix, OpContains:
+		args := compileArgs(e, t)
+		match := map[Op]func(string, string) bool{
+			OpPrefix:   strings.HasPrefix,
+			OpSuffix:   strings.HasSuffix,
+			OpContains: strings.Contains,
+		}[e.Op]
+		return func(v reflect.Value) (any, error) {
+			values, err := evalArgs(args, v)
+			if err != nil || values[0] == nil || values[1] == nil {
+				return nil, err
+			}
+			s, ok1 := asString(values[0])
+			substr, ok2 := asString(values[1])
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("%w: %s needs strings, got %T and %T", ErrType, e.Op, values[0], values[1])
+			}
+			return match(s, substr), nil
+		}
+	default:
+		args := compileArgs(e, t)
+		if (e.Op == OpEq || e.Op == OpNe) && (isNull(e.Args[0]) || isNull(e.Args[1])) {
+			// comparing to a null constant tests for null like IS NULL and IS NOT NULL
+			return func(v reflect.Value) (any, error) {
+				values, err := evalArgs(args, v)
+				if err != nil {
+					return nil, err
+				}
+				return (values[0] == nil && values[1] == nil) == (e.Op == OpEq), nil
+			}
+		}
+		return func(v reflect.Value) (any, error) {
+			values, err := evalArgs(args, v)
+			if err != nil || values[0] == nil || values[1] == nil {
+				return nil, err
+			}
+			return compareOp(e, values[0], values[1])
+		}
+	}
+}
+
+func compileArgs(e Expr, t reflect.Type) []evaluator {
+	args := make([]evaluator, len(e.Args))
+	for i, a := range e.Args {
+		args[i] = compile(a, t)
+	}
+	return args
+}
+
+func evalArgs(args []evaluator, v reflect.Value) ([]any, error) {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		var err error
+		if values[i], err = arg(v); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func asBool(e Expr, v any) (bool, error) {
+	if v == nil {
+		return false, nil
+	}
+	b := reflect.ValueOf(v)
+	if b.Kind() != reflect.Bool {
+		return false, fmt.Errorf("%w: %v is %T, not bool", ErrType, e, v)
+	}
+	return b.Bool(), nil
+}
+
+func asString(v any) (string, bool) {
+	s := reflect.ValueOf(v)
+	if s.Kind() != reflect.String {
+		return "", false
+	}
+	return s.String(), true
+}
+
+// isNull returns true if e is the nil constant
+func isNull(e Expr) bool {
+	return e.Op == OpConst && e.Value == nil
+}
+
+// compareOp applies the comparison e.Op to a and b, which are not null
+func compareOp(e Expr, a, b any) (bool, error) {
+	switch e.Op {
+	case OpEq:
+		return equal(e, a, b)
+	case OpNe:
+		eq, err := equal(e, a, b)
+		return !eq, err
+	}
+	c, err := compare(e, a, b)
+	if err != nil {
+		return false, err
+	}
+	switch e.Op {
+	case OpLt:
+		return c < 0, nil
+	case OpLe:
+		return c <= 0, nil
+	case OpGt:
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+func equal(e Expr, a, b any) (bool, error) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if categoryOf(va.Type()) == other {
+		if va.Type() != vb.Type() || !va.Comparable() {
+			return false, fmt.Errorf("%w: cannot compare %T and %T in %v", ErrType, a, b, e)
+		}
+		return va.Equal(vb), nil
+	}
+	c, err := compare(e, a, b)
+	return c == 0, err
+}
+
+// compare orders values of the same category, numbers of different types compare by value
+func compare(e Expr, a, b any) (int, error) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	c := categoryOf(va.Type())
+	if c != categoryOf(vb.Type()) {
+		return 0, fmt.Errorf("%w: cannot compare %T and %T in %v", ErrType, a, b, e)
+	}
+	switch c {
+	case numeric:
+		return compareNumbers(va, vb), nil
+	case text:
+		return strings.Compare(va.String(), vb.String()), nil
+	case boolean:
+		return cmp.Compare(boolInt(va.Bool()), boolInt(vb.Bool())), nil
+	case instant:
+		return a.(time.Time).Compare(b.(time.Time)), nil
+	default:
+		return 0, fmt.Errorf("%w: cannot order %T in %v", ErrType, a, e)
+	}
+}
+
+// Compare orders any two values, returning a negative number when a is before b, zero when equal
+// and a positive number otherwise
+// Values of the same category compare like in expressions, other values are ordered null, bool, number, string,
+// time then everything else, which compares as equal
+func Compare(a, b any) int {
+	if a == nil || b == nil {
+		return cmp.Compare(boolInt(a != nil), boolInt(b != nil))
+	}
+	ca, cb := categoryOf(reflect.TypeOf(a)), categoryOf(reflect.TypeOf(b))
+	if ca != cb || ca == other {
+		return cmp.Compare(rank(ca), rank(cb))
+	}
+	c, _ := compare(Expr{}, a, b)
+	return c
+}
+
+// rank orders categories for Compare
+func rank(c category) int {
+	switch c {
+	case boolean:
+		return 0
+	case numeric:
+		return 1
+	case text:
+		return 2
+	case instant:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func compareNumbers(a, b reflect.Value) int {
+	switch {
+	case a.CanFloat() || b.CanFloat():
+		return cmp.Compare(toFloat(a), toFloat(b))
+	case a.CanInt() && b.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanInt():
+		if a.Int() < 0 {
+			return -1
+		}
+		return cmp.Compare(uint64(a.Int()), b.Uint())
+	default:
+		return -compareNumbers(b, a)
+	}
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case v.CanFloat():
+		return v.Float()
+	case v.CanInt():
+		return float64(v.Int())
+	default:
+		return float64(v.Uint())
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,176 @@
+package expr
+
+import (
+	"cmp"
+	"errors"
+	"testing"
+	"time"
+)
+
+type Address struct {
+	City string
+}
+
+type Person struct {
+	*Address
+	Name    string
+	Age     int
+	Score   float64
+	Nick    *string
+	Born    time.Time
+	Tags    map[string]any
+	private int
+}
+
+func TestCompile(t *testing.T) {
+	nick := "Countess"
+	ada := Person{&Address{"London"}, "Ada", 36, 9.5, &nick, time.Date(1815, 12, 10, 0, 0, 0, 0, time.UTC), map[string]any{"math": true}, 0}
+	tests := []struct {
+		expr     Expr
+		expected any
+	}{
+		{Field("Name"), "Ada"},
+		{Field("City"), "London"},
+		{Field("Nick"), "Countess"},
+		{Field("Tags.math"), true},
+		{Field("Tags.missing"), nil},
+		{Field("Age").Gt(30).And(Field("Name").HasPrefix("A")), true},
+		{Field("Age").Eq(36.0), true},
+		{Field("Age").Lt(uint8(200)), true},
+		{Field("Score").Ge(10), false},
+		{Field("Name").Le("Ada"), true},
+		{Field("Nick").Eq(nil), false},
+		{Field("Nick").HasSuffix("ess"), true},
+		{Field("Tags.missing").Eq(nil), true},
+		{Field("Tags.missing").Gt(1), nil},
+		{Field("Tags.missing").Ne(1), nil},
+		{Field("Tags.missing").Ne(nil), false},
+		{Field("Tags.missing").Eq(1).Not(), nil},
+		{Field("Tags.missing").HasPrefix("a"), nil},
+		{Field("Tags.missing").In(1, 2), nil},
+		{Field("Age").In(nil, 36), true},
+		{Field("Age").In(nil, 1), nil},
+		{Field("Tags.missing").Eq(1).And(Field("Age").Gt(100)), false},
+		{Field("Tags.missing").Eq(1).And(Field("Age").Gt(30)), nil},
+		{Field("Tags.missing").Eq(1).Or(Field("Age").Gt(30)), true},
+		{Field("Tags.missing").Eq(1).Or(Field("Age").Gt(100)), nil},
+		{Field("Born").Lt(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)), true},
+		{Field("Name").In("Grace", "Ada"), true},
+		{Field("Age").In(), false},
+		{Field("Age").Lt(18).Or(Field("Name").Contains("d")), true},
+		{Field("Age").Lt(18).Not(), true},
+		{Field("Tags.math").And(Field("Age").Gt(100)), false},
+	}
+	for _, tt := range tests {
+		f, err := Compile[Person](tt.expr)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", tt.expr, err)
+			continue
+		}
+		result, err := f(ada)
+		if err != nil || result != tt.expected {
+			t.Errorf("Expected %v for %v, got %v, %v", tt.expected, tt.expr, result, err)
+		}
+	}
+}
+
+func TestCompileNilPointers(t *testing.T) {
+	f, err := Compile[*Person](Field("City").Eq(nil).And(Field("Nick").Eq(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*Person{{}, nil} {
+		if result, err := f(p); result != true || err != nil {
+			t.Errorf("Expected true for %v, got %v, %v", p, result, err)
+		}
+	}
+}
+
+func TestPredicate(t *testing.T) {
+	f, err := Predicate[map[string]any](Field("status").Eq("error").And(Field("ms").Gt(10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		value    map[string]any
+		expected bool
+	}{
+		{map[string]any{"status": "error", "ms": 12}, true},
+		{map[string]any{"status": "error", "ms": 5.5}, false},
+		{map[string]any{"status": "ok", "ms": 12}, false},
+		{map[string]any{}, false},
+	}
+	for _, tt := range tests {
+		if result, err := f(tt.value); result != tt.expected || err != nil {
+			t.Errorf("Expected %v for %v, got %v, %v", tt.expected, tt.value, result, err)
+		}
+	}
+}
+
+func TestPredicateNotBool(t *testing.T) {
+	_, err := Predicate[Person](Field("Age"))
+
+	if !errors.Is(err, ErrType) {
+		t.Errorf("Expected %v, got %v", ErrType, err)
+	}
+}
+
+func TestEval(t *testing.T) {
+	value := map[string]any{"user": map[string]any{"name": "Ada"}, "n": uint64(1 << 63)}
+	tests := []struct {
+		expr     Expr
+		expected any
+	}{
+		{Field("user.name").Eq("Ada"), true},
+		{Field("n").Gt(-1), true},
+		{Field("n").Gt(int64(1) << 62), true},
+		{Const(2).Ge(2.0), true},
+	}
+	for _, tt := range tests {
+		result, err := Eval(tt.expr, value)
+		if err != nil || result != tt.expected {
+			t.Errorf("Expected %v for %v, got %v, %v", tt.expected, tt.expr, result, err)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	value := map[string]any{"n": "one", "s": []int{1}, "b": 1}
+	tests := []Expr{
+		Field("n").Gt(0),
+		Field("s").Eq(Field("s")),
+		Field("n").HasPrefix("o").And(Field("b")),
+		Field("b").Contains("1"),
+		Field("n.x").Eq(1),
+	}
+	for _, e := range tests {
+		if _, err := Eval(e, value); !errors.Is(err, ErrType) {
+			t.Errorf("Expected %v for %v, got %v", ErrType, e, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	values := []any{nil, false, true, -1, uint8(2), 2.5, "a", "b", time.Unix(0, 0), []int{1}}
+	for i, a := range values {
+		for j, b := range values {
+			expected := cmp.Compare(i, j)
+			if result := Compare(a, b); result != expected {
+				t.Errorf("Expected %d comparing %v and %v, got %d", expected, a, b, result)
+			}
+		}
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	f, err := Predicate[int](Field("").Gt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := f(3); !result {
+		t.Errorf("Expected true")
+	}
+	if result := Field("").Eq(1).String(); result != ". == 1" {
+		t.Errorf("Expected . == 1, got %s", result)
+	}
+}
@@ -0,0 +1,139 @@
+// Package expr provides composable expression trees that can be evaluated, printed, serialized
+// and translated by other packages such as query
+//
+//	e := expr.Field("Age").Gt(30).And(expr.Field("Name").HasPrefix("A"))
+//	fmt.Println(e) // .Age > 30 and hasPrefix(.Name, "A")
+//	match, err := expr.Compile[User](e)
+//
+// Fields are named by their Go struct field or map key, a dotted name such as "Address.City" selects a nested field
+//
+// Null values follow SQL: comparisons and functions of null are null, not of null is null, and is null unless an
+// operand is false and or is null unless an operand is true. Predicates treat null as false, so a field that is null
+// matches neither .x == 5 nor .x != 5. Comparing to a nil constant tests for null instead
+package expr
+
+// Op is the operation of an Expr node
+type Op string
+
+const (
+	OpField    Op = "field"
+	OpConst    Op = "const"
+	OpEq       Op = "eq"
+	OpNe       Op = "ne"
+	OpLt       Op = "lt"
+	OpLe       Op = "le"
+	OpGt       Op = "gt"
+	OpGe       Op = "ge"
+	OpAnd      Op = "and"
+	OpOr       Op = "or"
+	OpNot      Op = "not"
+	OpIn       Op = "in"
+	OpPrefix   Op = "prefix"
+	OpSuffix   Op = "suffix"
+	OpContains Op = "contains"
+)
+
+// Expr is a node in an expression tree
+// Leaves are fields and constants, every other node applies Op to Args
+// Expr encodes to JSON with encoding/json, integer constants decode as int64 and other numbers as float64
+type Expr struct {
+	Op Op `json:"op"`
+	// Name is the field of an OpField node
+	Name string `json:"name,omitempty"`
+	// Value is the constant of an OpConst node
+	Value any    `json:"value,omitempty"`
+	Args  []Expr `json:"args,omitempty"`
+}
+
+// Field returns an expression for the struct field or map key with the given name
+// The empty name is the value itself, which is formatted as .
+func Field(name string) Expr {
+	return Expr{Op: OpField, Name: name}
+}
+
+// Const returns an expression for a constant value
+func Const(v any) Expr {
+	return Expr{Op: OpConst, Value: v}
+}
+
+// Eq returns an expression that is true when e equals v, v can be a constant or an Expr
+// Comparing to nil tests for NULL
+func (e Expr) Eq(v any) Expr {
+	return e.binary(OpEq, v)
+}
+
+// Ne returns an expression that is true when e does not equal v, v can be a constant or an Expr
+// Comparing to nil tests for NOT NULL
+func (e Expr) Ne(v any) Expr {
+	return e.binary(OpNe, v)
+}
+
+// Lt returns an expression that is true when e is less than v, v can be a constant or an Expr
+func (e Expr) Lt(v any) Expr {
+	return e.binary(OpLt, v)
+}
+
+// Le returns an expression that is true when e is less than or equal to v, v can be a constant or an Expr
+func (e Expr) Le(v any) Expr {
+	return e.binary(OpLe, v)
+}
+
+// Gt returns an expression that is true when e is greater than v, v can be a constant or an Expr
+func (e Expr) Gt(v any) Expr {
+	return e.binary(OpGt, v)
+}
+
+// Ge returns an expression that is true when e is greater than or equal to v, v can be a constant or an Expr
+func (e Expr) Ge(v any) Expr {
+	return e.binary(OpGe, v)
+}
+
+// And returns an expression that is true when e and all of others are true
+func (e Expr) And(others ...Expr) Expr {
+	return Expr{Op: OpAnd, Args: append([]Expr{e}, others...)}
+}
+
+// Or returns an expression that is true when e or any of others is true
+func (e Expr) Or(others ...Expr) Expr {
+	return Expr{Op: OpOr, Args: append([]Expr{e}, others...)}
+}
+
+// Not returns an expression that is true when e is false
+func (e Expr) Not() Expr {
+	return Expr{Op: OpNot, Args: []Expr{e}}
+}
+
+// In returns an expression that is true when e equals any of values
+func (e Expr) In(values ...any) Expr {
+	args := []Expr{e}
+	for _, v := range values {
+		args = append(args, toExpr(v))
+	}
+	return Expr{Op: OpIn, Args: args}
+}
+
+// HasPrefix returns an expression that is true when the string e starts with prefix
+func (e Expr) HasPrefix(prefix string) Expr {
+	return e.binary(OpPrefix, prefix)
+}
+
+// HasSuffix returns an expression that is true when the string e ends with suffix
+func (e Expr) HasSuffix(suffix string) Expr {
+	return e.binary(OpSuffix, suffix)
+}
+
+// Contains returns an expression that is true when the string e contains substr
+func (e Expr) Contains(substr string) Expr {
+	return e.binary(OpContains, substr)
+}
+
+func (e Expr) binary(op Op, v any) Expr {
+	return Expr{Op: op, Args: []Expr{e, toExpr(v)}}
+}
+
+func toExpr(v any) Expr {
+	if e, ok := v.(Expr); ok {
+		return e
+	}
+	return Const(v)
+}
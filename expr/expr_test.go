@@ -0,0 +1,88 @@
+package expr
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExprTree(t *testing.T) {
+	e := Field("Age").Gt(30).And(Field("Name").HasPrefix("A"))
+	expected := Expr{Op: OpAnd, Args: []Expr{
+		{Op: OpGt, Args: []Expr{{Op: OpField, Name: "Age"}, {Op: OpConst, Value: 30}}},
+		{Op: OpPrefix, Args: []Expr{{Op: OpField, Name: "Name"}, {Op: OpConst, Value: "A"}}},
+	}}
+
+	if !reflect.DeepEqual(e, expected) {
+		t.Errorf("Expected %v, got %v", expected, e)
+	}
+}
+
+func TestExprOperand(t *testing.T) {
+	e := Field("Min").Le(Field("Max"))
+	expected := Expr{Op: OpLe, Args: []Expr{{Op: OpField, Name: "Min"}, {Op: OpField, Name: "Max"}}}
+
+	if !reflect.DeepEqual(e, expected) {
+		t.Errorf("Expected %v, got %v", expected, e)
+	}
+}
+
+func TestExprIn(t *testing.T) {
+	e := Field("ID").In(1, Field("Other"))
+	expected := Expr{Op: OpIn, Args: []Expr{{Op: OpField, Name: "ID"}, {Op: OpConst, Value: 1}, {Op: OpField, Name: "Other"}}}
+
+	if !reflect.DeepEqual(e, expected) {
+		t.Errorf("Expected %v, got %v", expected, e)
+	}
+}
+
+func TestExprString(t *testing.T) {
+	tests := []struct {
+		expr     Expr
+		expected string
+	}{
+		{Field("Age").Gt(30).And(Field("Name").HasPrefix("A")), `.Age > 30 and hasPrefix(.Name, "A")`},
+		{Field("A").Eq(1).Or(Field("B").Eq(2)).And(Field("C").Ne(nil)), `(.A == 1 or .B == 2) and .C != null`},
+		{Field("A").Eq(1).And(Field("B").Eq(2)).Or(Field("C").Eq(true)), `.A == 1 and .B == 2 or .C == true`},
+		{Field("Age").Lt(18).Not(), `not (.Age < 18)`},
+		{Field("Active").Not(), `not .Active`},
+		{Field("ID").In(1, 2.5, "x"), `.ID in [1, 2.5, "x"]`},
+		{Field("X").Gt(3.0).Or(Field("X").Lt(float32(-1e21))), `.X > 3.0 or .X < -1e+21`},
+		{Field("Address.City").Contains(`"`), `contains(.Address.City, "\"")`},
+		{Field("A").Eq(Field("B").Eq(1)), `.A == (.B == 1)`},
+	}
+	for _, tt := range tests {
+		if result := tt.expr.String(); result != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, result)
+		}
+	}
+}
+
+func TestExprJSON(t *testing.T) {
+	e := Field("Age").Gt(30).And(Field("Score").Le(2.5), Field("Name").In("A", nil), Field("Active").Eq(true))
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result Expr
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	expected := Field("Age").Gt(int64(30)).And(Field("Score").Le(2.5), Field("Name").In("A", nil), Field("Active").Eq(true))
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, result)
+	}
+}
+
+func TestExprJSONFormat(t *testing.T) {
+	data, err := json.Marshal(Field("Age").Gt(30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"op":"gt","args":[{"op":"field","name":"Age"},{"op":"const","value":30}]}`
+
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
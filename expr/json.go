@@ -0,0 +1,39 @@
+package expr
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// UnmarshalJSON decodes an expression encoded with encoding/json
+// Integer constants decode as int64 and other numbers as float64 so that they compare like the original values
+func (e *Expr) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Op    Op              `json:"op"`
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+		Args  []Expr          `json:"args"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = Expr{Op: raw.Op, Name: raw.Name, Args: raw.Args}
+	if len(raw.Value) == 0 {
+		return nil
+	}
+	d := json.NewDecoder(bytes.NewReader(raw.Value))
+	d.UseNumber()
+	if err := d.Decode(&e.Value); err != nil {
+		return err
+	}
+	if n, ok := e.Value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			e.Value = i
+		} else if f, err := n.Float64(); err == nil {
+			e.Value = f
+		} else {
+			return err
+		}
+	}
+	return nil
+}
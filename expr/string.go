@@ -0,0 +1,129 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var symbols = map[Op]string{
+	OpEq:  "==",
+	OpNe:  "!=",
+	OpLt:  "<",
+	OpLe:  "<=",
+	OpGt:  ">",
+	OpGe:  ">=",
+	OpAnd: "and",
+	OpOr:  "or",
+}
+
+var functions = map[Op]string{
+	OpPrefix:   "hasPrefix",
+	OpSuffix:   "hasSuffix",
+	OpContains: "contains",
+}
+
+// precedence orders the operators from loosest to tightest binding
+func precedence(op Op) int {
+	switch op {
+	case OpOr:
+		return 1
+	case OpAnd:
+		return 2
+	case OpNot:
+		return 3
+	case OpEq, OpNe, OpLt, OpLe, OpGt, OpGe, OpIn:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// String formats the expression with fields as .Name, for example .Age > 30 and hasPrefix(.Name, "A")
+func (e Expr) String() string {
+	var b strings.Builder
+	e.format(&b, 0)
+	return b.String()
+}
+
+// format writes e, parenthesized if it binds less tightly than parent
+func (e Expr) format(b *strings.Builder, parent int) {
+	p := precedence(e.Op)
+	if p < parent {
+		b.WriteString("(")
+		defer b.WriteString(")")
+	}
+	switch e.Op {
+	case OpField:
+		b.WriteString("." + e.Name)
+	case OpConst:
+		b.WriteString(formatValue(e.Value))
+	case OpNot:
+		// only fields, constants and functions are left unparenthesized since not binds less tightly than comparisons
+		b.WriteString("not ")
+		e.formatArgs(b, "", precedence(OpField))
+	case OpIn:
+		e.formatArgs(b, "", p+1)
+	default:
+		if fn, ok := functions[e.Op]; ok {
+			b.WriteString(fn + "(")
+			e.formatArgs(b, ", ", 0)
+			b.WriteString(")")
+			return
+		}
+		symbol, ok := symbols[e.Op]
+		if !ok {
+			symbol = string(e.Op)
+		}
+		// comparisons do not chain so their operands are parenthesized at equal precedence
+		if e.Op != OpAnd && e.Op != OpOr {
+			p++
+		}
+		e.formatArgs(b, " "+symbol+" ", p)
+	}
+}
+
+func (e Expr) formatArgs(b *strings.Builder, sep string, parent int) {
+	if e.Op == OpIn && len(e.Args) > 0 {
+		e.Args[0].format(b, parent)
+		b.WriteString(" in [")
+		for i, a := range e.Args[1:] {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			a.format(b, 0)
+		}
+		b.WriteString("]")
+		return
+	}
+	for i, a := range e.Args {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		a.format(b, parent)
+	}
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return formatFloat(v, 64)
+	case float32:
+		return formatFloat(float64(v), 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatFloat formats f so that it reads back as a float, 3 is formatted as 3.0
+func formatFloat(f float64, bitSize int) string {
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,298 @@
+package enumerable
+
+import (
+	"cmp"
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sdehm/go-enumerable/expr"
+	"github.com/sdehm/go-enumerable/query"
+)
+
+type person struct {
+	Name string
+	Age  int
+}
+
+var people = []person{{"Ada", 36}, {"Alan", 41}, {"Grace", 85}, {"Anna", 12}}
+
+func TestFilterExpr(t *testing.T) {
+	e := New(people).FilterExpr(expr.Field("Age").Gt(30).And(expr.Field("Name").HasPrefix("A")))
+	result := e.ToList()
+	expected := []person{{"Ada", 36}, {"Alan", 41}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestFilterExprMatchesFilter(t *testing.T) {
+	result := New(people).FilterExpr(expr.Field("Age").Le(36).Or(expr.Field("Name").Eq("Grace")).Not()).ToList()
+	expected := New(people).Filter(func(p person) bool { return !(p.Age <= 36 || p.Name == "Grace") }).ToList()
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestFilterExprMap(t *testing.T) {
+	values := []map[string]any{{"status": "error", "ms": 12}, {"status": "ok", "ms": 3}, {"ms": 7}}
+	// like in SQL a missing status is null and is neither equal nor unequal to "ok"
+	result := New(values).FilterExpr(expr.Field("status").Ne("ok")).ToList()
+	expected := []map[string]any{values[0]}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestFilterExprTypeError(t *testing.T) {
+	_, err := New(people).FilterExpr(expr.Field("Age").HasPrefix("1")).ToListErr()
+
+	if !errors.Is(err, expr.ErrType) {
+		t.Errorf("Expected %v, got %v", expr.ErrType, err)
+	}
+}
+
+func TestFilterExprEvalError(t *testing.T) {
+	values := []map[string]any{{"n": 1}, {"n": "two"}}
+	_, err := New(values).FilterExpr(expr.Field("n").Gt(0)).ToListErr()
+
+	if !errors.Is(err, expr.ErrType) {
+		t.Errorf("Expected %v, got %v", expr.ErrType, err)
+	}
+}
+
+func TestMapExpr(t *testing.T) {
+	result, err := New(people).MapExpr(expr.Field("Age").Gt(40)).ToListErr()
+	expected := []any{false, true, true, false}
+
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v, %v", expected, result, err)
+	}
+}
+
+func TestMapExprTypeError(t *testing.T) {
+	_, err := New(people).MapExpr(expr.Field("Height")).ToListErr()
+
+	if !errors.Is(err, expr.ErrType) {
+		t.Errorf("Expected %v, got %v", expr.ErrType, err)
+	}
+}
+
+type nullablePerson struct {
+	ID   int     `db:"id"`
+	Name *string `db:"name"`
+	Age  *int    `db:"age"`
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestFilterExprAgreesWithSQL(t *testing.T) {
+	rows := []nullablePerson{
+		{1, ptr("Ada"), ptr(36)},
+		{2, nil, ptr(41)},
+		{3, ptr("Alan"), nil},
+		{4, nil, nil},
+		{5, ptr("Grace"), ptr(85)},
+	}
+	name, age := expr.Field("Name"), expr.Field("Age")
+	filters := []expr.Expr{
+		age.Ne(36),
+		age.Eq(36).Not(),
+		age.Gt(40).Or(name.Eq("Ada")),
+		age.Lt(50).And(name.Ne("Ada")),
+		age.Lt(50).And(name.Ne("Ada")).Not(),
+		name.HasPrefix("A").Not(),
+		name.HasPrefix("a"),
+		name.Contains("la").Or(name.HasSuffix("CE")),
+		name.Eq("ada").Or(name.In("alan", "Grace")),
+		name.Gt("a"),
+		age.In(36, 85).Not(),
+		age.In(nil, 36),
+		age.In(nil, 36).Not(),
+		age.Eq(nil),
+		name.Ne(nil).Not(),
+		expr.Const(nil).Eq(name),
+		age.In().Not(),
+		age.Le(expr.Field("ID")).Not(),
+	}
+	for _, f := range filters {
+		sql, args, err := query.From[nullablePerson]("people").Filter(f).SQL(query.SQLite)
+		if err != nil {
+			t.Fatalf("Unexpected error for %v: %v", f, err)
+		}
+		where := sql[strings.Index(sql, " WHERE ")+len(" WHERE "):]
+		expected := []int{}
+		for _, row := range rows {
+			if sqlWhere(t, where, args, row) == true {
+				expected = append(expected, row.ID)
+			}
+		}
+		result := Transform(New(rows).FilterExpr(f), func(p nullablePerson) int { return p.ID }).ToList()
+
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected %v for %v as in %s, got %v", expected, f, where, result)
+		}
+	}
+}
+
+// sqlWhere evaluates the WHERE clause of SQLite statements compiled by query with SQL's three valued logic,
+// returning nil when the condition is unknown
+func sqlWhere(t *testing.T, where string, args []any, row nullablePerson) any {
+	t.Helper()
+	tokens := regexp.MustCompile(`"\w+"|'[^']*'|\w+|<>|<=|>=|\S`).FindAllString(where, -1)
+	w := &sqlEvaluator{tokens: tokens, args: args, row: row}
+	result := w.or()
+	if w.pos != len(tokens) {
+		t.Fatalf("Unexpected %q in %s", tokens[w.pos], where)
+	}
+	return result
+}
+
+type sqlEvaluator struct {
+	tokens []string
+	pos    int
+	args   []any
+	row    nullablePerson
+}
+
+func (w *sqlEvaluator) accept(token string) bool {
+	if w.pos < len(w.tokens) && w.tokens[w.pos] == token {
+		w.pos++
+		return true
+	}
+	return false
+}
+
+func (w *sqlEvaluator) or() any {
+	result := w.and()
+	for w.accept("OR") {
+		right := w.and()
+		if result == true || right == true {
+			result = true
+		} else if result == nil || right == nil {
+			result = nil
+		}
+	}
+	return result
+}
+
+func (w *sqlEvaluator) and() any {
+	result := w.not()
+	for w.accept("AND") {
+		right := w.not()
+		if result == false || right == false {
+			result = false
+		} else if result == nil || right == nil {
+			result = nil
+		}
+	}
+	return result
+}
+
+func (w *sqlEvaluator) not() any {
+	if !w.accept("NOT") {
+		return w.predicate()
+	}
+	if b, ok := w.not().(bool); ok {
+		return !b
+	}
+	return nil
+}
+
+func (w *sqlEvaluator) predicate() any {
+	if w.accept("(") {
+		result := w.or()
+		w.accept(")")
+		return result
+	}
+	left := w.value()
+	switch {
+	case w.accept("IS"):
+		not := w.accept("NOT")
+		w.accept("NULL")
+		return (left == nil) != not
+	case w.accept("IN"):
+		w.accept("(")
+		var result any = false
+		for ok := true; ok; ok = w.accept(",") {
+			right := w.value()
+			if left != nil && left == right {
+				result = true
+			} else if result != true && (left == nil || right == nil) {
+				result = nil
+			}
+		}
+		w.accept(")")
+		return result
+	case w.accept("GLOB"):
+		pattern := w.value()
+		if left == nil {
+			return nil
+		}
+		glob := regexp.MustCompile(`\[(.)\]|\*|\?|[^*?\[]+`).ReplaceAllStringFunc(pattern.(string), func(s string) string {
+			switch s {
+			case "*":
+				return ".*"
+			case "?":
+				return "."
+			}
+			if len(s) == 3 && s[0] == '[' {
+				s = s[1:2]
+			}
+			return regexp.QuoteMeta(s)
+		})
+		return regexp.MustCompile("^" + glob + "$").MatchString(left.(string))
+	}
+	op := w.tokens[w.pos]
+	w.pos++
+	right := w.value()
+	if left == nil || right == nil {
+		return nil
+	}
+	c := 0
+	switch left := left.(type) {
+	case int:
+		c = cmp.Compare(left, right.(int))
+	case string:
+		c = cmp.Compare(left, right.(string))
+	}
+	return map[string]bool{"=": c == 0, "<>": c != 0, "<": c < 0, "<=": c <= 0, ">": c > 0, ">=": c >= 0}[op]
+}
+
+// value returns a column of the row, a parameter or a number, nil for NULL
+// Strings are compared byte by byte, so an explicit BINARY collation is skipped
+func (w *sqlEvaluator) value() any {
+	token := w.tokens[w.pos]
+	w.pos++
+	if w.accept("COLLATE") {
+		w.accept("BINARY")
+	}
+	switch token {
+	case `"id"`:
+		return w.row.ID
+	case `"name"`:
+		if w.row.Name == nil {
+			return nil
+		}
+		return *w.row.Name
+	case `"age"`:
+		if w.row.Age == nil {
+			return nil
+		}
+		return *w.row.Age
+	case "?":
+		v := w.args[0]
+		w.args = w.args[1:]
+		return v
+	}
+	n, _ := strconv.Atoi(token)
+	return n
+}
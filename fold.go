@@ -0,0 +1,38 @@
+package enumerable
+
+// Fold the Enumerable[T] into an accumulator of type A starting from seed
+// Returns seed if the Enumerable[T] is empty
+func Fold[T any, A any](e Enumerable[T], seed A, f func(A, T) A) A {
+	s := newScope()
+	defer s.close()
+	result := seed
+	next := e.run().iterate(s)
+	for v, ok := next(); ok; v, ok = next() {
+		result = f(result, v)
+	}
+	return result
+}
+
+// Aggregate folds the Enumerable[T] starting from seed and converts the final accumulator with a result selector
+func Aggregate[T any, A any, R any](e Enumerable[T], seed A, f func(A, T) A, result func(A) R) R {
+	return result(Fold(e, seed, f))
+}
+
+// Scan returns every intermediate accumulator of folding the Enumerable[T] starting from seed
+// The seed itself is not included, so the result has one value per input value
+// Evaluates lazily, call apply to evaluate
+func Scan[T any, A any](e Enumerable[T], seed A, f func(A, T) A) Enumerable[A] {
+	return fromSource(func(s *scope) iterator[A] {
+		next := e.run().iterate(s)
+		acc := seed
+		return func() (A, bool) {
+			v, ok := next()
+			if !ok {
+				var zero A
+				return zero, false
+			}
+			acc = f(acc, v)
+			return acc, true
+		}
+	})
+}
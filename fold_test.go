@@ -0,0 +1,86 @@
+package enumerable
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result := Fold(e, "", func(a string, i int) string { return a + strconv.Itoa(i) })
+	expected := "123"
+
+	if result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
+
+func TestFoldEmpty(t *testing.T) {
+	e := New([]int{})
+	result := Fold(e, 10, func(a int, i int) int { return a + i })
+	expected := 10
+
+	if result != expected {
+		t.Errorf("Expected %d, got %d", expected, result)
+	}
+}
+
+func TestFoldAfterFilter(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result := Fold(e.Filter(func(i int) bool { return i > 1 }), 0, func(a int, i int) int { return a + i })
+	expected := 5
+
+	if result != expected {
+		t.Errorf("Expected %d, got %d", expected, result)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	e := New([]int{1, 2, 3, 4})
+	type acc struct{ sum, count int }
+	result := Aggregate(e, acc{},
+		func(a acc, i int) acc { return acc{a.sum + i, a.count + 1} },
+		func(a acc) float64 { return float64(a.sum) / float64(a.count) })
+	expected := 2.5
+
+	if result != expected {
+		t.Errorf("Expected %f, got %f", expected, result)
+	}
+}
+
+func TestScan(t *testing.T) {
+	e := New([]int{1, 2, 3, 4})
+	result := Scan(e, 0, func(a int, i int) int { return a + i }).Apply()
+	expected := New([]int{1, 3, 6, 10})
+
+	if len(result.values) != 4 {
+		t.Errorf("Expected 4 values, got %d", len(result.values))
+	}
+	for i, v := range result.values {
+		if v != expected.values[i] {
+			t.Errorf("Expected %d, got %d", expected.values[i], v)
+		}
+	}
+}
+
+func TestScanEmpty(t *testing.T) {
+	e := New([]int{})
+	result := Scan(e, 0, func(a int, i int) int { return a + i }).Apply()
+
+	if len(result.values) != 0 {
+		t.Errorf("Expected 0 values, got %d", len(result.values))
+	}
+}
+
+func TestScanReevaluates(t *testing.T) {
+	e := Scan(New([]int{1, 2}), 0, func(a int, i int) int { return a + i })
+	e.ToList()
+	result := e.ToList()
+	expected := []int{1, 3}
+
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("Expected %d, got %d", expected[i], v)
+		}
+	}
+}
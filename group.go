@@ -0,0 +1,209 @@
+package enumerable
+
+import "hash/maphash"
+
+// Group is a key and the values of an Enumerable that have that key
+type Group[K comparable, T any] struct {
+	Key    K
+	Values []T
+}
+
+// GroupBy groups the values of the Enumerable[T] by key
+// Groups are ordered by the first appearance of their key and values keep their original order
+// Evaluates lazily, call apply to evaluate
+func GroupBy[T any, K comparable](e Enumerable[T], key func(T) K) Enumerable[Group[K, T]] {
+	return fromSource(func(s *scope) iterator[Group[K, T]] {
+		next := e.run().iterate(s)
+		return deferred(func() []Group[K, T] {
+			g := grouping[K, T]{}
+			for v, ok := next(); ok; v, ok = next() {
+				g.add(key(v), v)
+			}
+			return g.groups
+		})
+	})
+}
+
+// GroupByExternal groups the values of the Enumerable[T] by key like GroupBy while holding at most
+// options.MaxInMemory values in memory
+// When the limit is exceeded values are hash partitioned by key into temporary files that are grouped one at a time,
+// groups are then ordered by partition and only values keep their original order
+// Partitions holding more than options.MaxInMemory values are partitioned again, so more values are only held
+// when they share a key
+// Temporary files are removed when the terminal operation returns
+// Evaluates lazily, call apply to evaluate
+func GroupByExternal[T any, K comparable](e Enumerable[T], key func(T) K, options ExternalOptions[T]) Enumerable[Group[K, T]] {
+	options = options.withDefaults()
+	return fromSource(func(s *scope) iterator[Group[K, T]] {
+		next := e.run().iterate(s)
+		var groups iterator[Group[K, T]]
+		return func() (Group[K, T], bool) {
+			if groups == nil {
+				groups = groupExternal(s, next, key, options)
+			}
+			return groups()
+		}
+	})
+}
+
+func groupExternal[T any, K comparable](s *scope, next iterator[T], key func(T) K, options ExternalOptions[T]) iterator[Group[K, T]] {
+	g := &grouping[K, T]{}
+	held := 0
+	var spilled *partitions[T]
+	hash := func(v T, level int) uint64 { return hashKey(key(v), level) }
+	for v, ok := next(); ok; v, ok = next() {
+		if err := options.Context.Err(); err != nil {
+			s.fail(err)
+			return emptyIterator[Group[K, T]]
+		}
+		if spilled == nil {
+			g.add(key(v), v)
+			held++
+			if held < options.MaxInMemory {
+				continue
+			}
+			// move everything held so far to the partitions
+			spilled = newPartitions(s, options, hash)
+			for _, group := range g.groups {
+				for _, v := range group.Values {
+					if err := spilled.write(v); err != nil {
+						s.fail(err)
+						return emptyIterator[Group[K, T]]
+					}
+				}
+			}
+			g = nil
+			continue
+		}
+		if err := spilled.write(v); err != nil {
+			s.fail(err)
+			return emptyIterator[Group[K, T]]
+		}
+	}
+	if spilled == nil {
+		return New(g.groups).iterate(s)
+	}
+	return eachPartition(s, spilled, func(next iterator[T]) iterator[Group[K, T]] {
+		return deferred(func() []Group[K, T] {
+			g := grouping[K, T]{}
+			for v, ok := next(); ok; v, ok = next() {
+				g.add(key(v), v)
+			}
+			return g.groups
+		})
+	})
+}
+
+// Distinct returns the values of the Enumerable[T] without duplicates, keeping the first appearance of each value
+// Evaluates lazily, call apply to evaluate
+func Distinct[T comparable](e Enumerable[T]) Enumerable[T] {
+	return DistinctBy(e, func(v T) T { return v })
+}
+
+// DistinctBy returns the values of the Enumerable[T] without duplicate keys, keeping the first value with each key
+// Evaluates lazily, call apply to evaluate
+func DistinctBy[T any, K comparable](e Enumerable[T], key func(T) K) Enumerable[T] {
+	return e.stream("Distinct", func(next iterator[T]) iterator[T] {
+		return distinctBy(next, key)
+	})
+}
+
+func distinctBy[T any, K comparable](next iterator[T], key func(T) K) iterator[T] {
+	seen := map[K]struct{}{}
+	return func() (T, bool) {
+		for {
+			v, ok := next()
+			if !ok {
+				return v, false
+			}
+			k := key(v)
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				return v, true
+			}
+		}
+	}
+}
+
+// DistinctExternal returns the values of the Enumerable[T] without duplicates like Distinct while holding at most
+// options.MaxInMemory values in memory
+// The first options.MaxInMemory distinct values are returned as they are found, later values are hash partitioned
+// into temporary files that are deduplicated one at a time and returned in partition order
+// Partitions holding more than options.MaxInMemory values are partitioned again until they fit or hold a single value
+// Temporary files are removed when the terminal operation returns
+// Evaluates lazily, call apply to evaluate
+func DistinctExternal[T comparable](e Enumerable[T], options ExternalOptions[T]) Enumerable[T] {
+	options = options.withDefaults()
+	return e.streamScoped("DistinctExternal", func(s *scope, next iterator[T]) iterator[T] {
+		seen := map[T]struct{}{}
+		var spilled *partitions[T]
+		var rest iterator[T]
+		return func() (T, bool) {
+			for seen != nil {
+				v, ok := next()
+				if err := options.Context.Err(); err != nil {
+					s.fail(err)
+				}
+				if !ok || s.err != nil {
+					// every value written to the partitions is missing from seen so it is no longer needed
+					seen = nil
+					break
+				}
+				if _, ok := seen[v]; ok {
+					continue
+				}
+				if len(seen) < options.MaxInMemory {
+					seen[v] = struct{}{}
+					return v, true
+				}
+				if spilled == nil {
+					spilled = newPartitions(s, options, hashKey[T])
+				}
+				if err := spilled.write(v); err != nil {
+					s.fail(err)
+				}
+			}
+			if spilled == nil || s.err != nil {
+				var zero T
+				return zero, false
+			}
+			if rest == nil {
+				rest = eachPartition(s, spilled, func(next iterator[T]) iterator[T] {
+					return distinctBy(next, func(v T) T { return v })
+				})
+			}
+			return rest()
+		}
+	})
+}
+
+// grouping collects values into groups ordered by the first appearance of their key
+type grouping[K comparable, T any] struct {
+	index  map[K]int
+	groups []Group[K, T]
+}
+
+func (g *grouping[K, T]) add(k K, v T) {
+	if g.index == nil {
+		g.index = map[K]int{}
+	}
+	i, ok := g.index[k]
+	if !ok {
+		i = len(g.groups)
+		g.index[k] = i
+		g.groups = append(g.groups, Group[K, T]{Key: k})
+	}
+	g.groups[i].Values = append(g.groups[i].Values, v)
+}
+
+var hashSeed = maphash.MakeSeed()
+
+// hashKey hashes k for a level of partitioning, each level spreads keys independently of the levels before it
+func hashKey[K comparable](k K, level int) uint64 {
+	return maphash.Comparable(hashSeed, leveledKey[K]{level, k})
+}
+
+type leveledKey[K comparable] struct {
+	level int
+	key   K
+}
@@ -0,0 +1,221 @@
+package enumerable
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	e := New([]string{"apple", "avocado", "banana", "cherry", "blueberry"})
+	result := GroupBy(e, func(s string) byte { return s[0] }).ToList()
+	expected := []Group[byte, string]{
+		{'a', []string{"apple", "avocado"}},
+		{'b', []string{"banana", "blueberry"}},
+		{'c', []string{"cherry"}},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestGroupByEmpty(t *testing.T) {
+	result := GroupBy(New([]int{}), func(i int) int { return i }).ToList()
+
+	if len(result) != 0 {
+		t.Errorf("Expected 0 values, got %d", len(result))
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	e := New([]int{3, 1, 3, 2, 1})
+	result := Distinct(e).ToList()
+	expected := []int{3, 1, 2}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestDistinctBy(t *testing.T) {
+	e := New([]string{"a", "bb", "c", "dd", "eee"})
+	result := DistinctBy(e, func(s string) int { return len(s) }).ToList()
+	expected := []string{"a", "bb", "eee"}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func externalValues() Enumerable[int] {
+	values := make([]int, 200)
+	for i := range values {
+		values[i] = (i * 7919) % 37
+	}
+	return New(values)
+}
+
+func sortedGroups(groups []Group[int, int]) []Group[int, int] {
+	groups = slices.Clone(groups)
+	slices.SortFunc(groups, func(a, b Group[int, int]) int { return cmp.Compare(a.Key, b.Key) })
+	return groups
+}
+
+func TestGroupByExternal(t *testing.T) {
+	dir := t.TempDir()
+	key := func(i int) int { return i % 5 }
+	options := ExternalOptions[int]{MaxInMemory: 3, TempDir: dir, Partitions: 4}
+	e := GroupByExternal(externalValues(), key, options)
+	files := 0
+	result := []Group[int, int]{}
+	e.ForEach(func(g Group[int, int]) {
+		files = max(files, tempFiles(t, dir))
+		result = append(result, g)
+	})
+	expected := GroupBy(externalValues(), key).ToList()
+
+	if !reflect.DeepEqual(sortedGroups(result), sortedGroups(expected)) {
+		t.Errorf("Expected %v, got %v", sortedGroups(expected), sortedGroups(result))
+	}
+	if files == 0 {
+		t.Errorf("Expected values to be spilled to temporary files")
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Expected temporary files to be removed, found %d", n)
+	}
+}
+
+func TestGroupByExternalSplitsPartitions(t *testing.T) {
+	dir := t.TempDir()
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = (i * 7919) % 200
+	}
+	key := func(i int) int { return i }
+	options := ExternalOptions[int]{MaxInMemory: 10, TempDir: dir, Partitions: 2}
+	result, err := GroupByExternal(New(values), key, options).ToListErr()
+	expected := GroupBy(New(values), key).ToList()
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if !reflect.DeepEqual(sortedGroups(result), sortedGroups(expected)) {
+		t.Errorf("Expected %v, got %v", sortedGroups(expected), sortedGroups(result))
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Expected temporary files to be removed, found %d", n)
+	}
+}
+
+func TestPartitionsSplitUntilTheyFit(t *testing.T) {
+	s := newScope()
+	defer s.release()
+	options := ExternalOptions[int]{MaxInMemory: 10, TempDir: t.TempDir(), Partitions: 2}.withDefaults()
+	p := newPartitions(s, options, hashKey[int])
+	for i := range 1000 {
+		p.write(i % 200)
+	}
+	// a key with more values than fit in memory cannot be split
+	for range 50 {
+		p.write(7)
+	}
+	total := 0
+	next := eachPartition(s, p, func(next iterator[int]) iterator[int] {
+		count := 0
+		keys := map[int]bool{}
+		for v, ok := next(); ok; v, ok = next() {
+			count++
+			keys[v] = true
+		}
+		if count > options.MaxInMemory && len(keys) > 1 {
+			t.Errorf("Expected at most %d values or a single key, got %d values of %d keys", options.MaxInMemory, count, len(keys))
+		}
+		total += count
+		return emptyIterator[int]
+	})
+	for _, ok := next(); ok; _, ok = next() {
+	}
+
+	if s.err != nil {
+		t.Errorf("Expected nil, got %v", s.err)
+	}
+	if total != 1050 {
+		t.Errorf("Expected 1050 values, got %d", total)
+	}
+}
+
+func TestDistinctExternalDuplicates(t *testing.T) {
+	values := make([]int, 500)
+	for i := range values {
+		values[i] = i % 3
+	}
+	options := ExternalOptions[int]{MaxInMemory: 2, TempDir: t.TempDir(), Partitions: 2}
+	result, err := DistinctExternal(New(values), options).ToListErr()
+	slices.Sort(result)
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if !reflect.DeepEqual(result, []int{0, 1, 2}) {
+		t.Errorf("Expected %v, got %v", []int{0, 1, 2}, result)
+	}
+}
+
+func TestGroupByExternalInMemory(t *testing.T) {
+	key := func(i int) int { return i % 5 }
+	options := ExternalOptions[int]{TempDir: t.TempDir()}
+	result := GroupByExternal(externalValues(), key, options).ToList()
+	expected := GroupBy(externalValues(), key).ToList()
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestDistinctExternal(t *testing.T) {
+	dir := t.TempDir()
+	options := ExternalOptions[int]{MaxInMemory: 5, TempDir: dir, Partitions: 3}
+	e := DistinctExternal(externalValues(), options)
+	files := 0
+	result := []int{}
+	e.ForEach(func(i int) {
+		files = max(files, tempFiles(t, dir))
+		result = append(result, i)
+	})
+	expected := Distinct(externalValues()).ToList()
+
+	if !reflect.DeepEqual(result[:5], expected[:5]) {
+		t.Errorf("Expected the first values in order %v, got %v", expected[:5], result[:5])
+	}
+	slices.Sort(result)
+	slices.Sort(expected)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if files == 0 {
+		t.Errorf("Expected values to be spilled to temporary files")
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Expected temporary files to be removed, found %d", n)
+	}
+}
+
+func TestDistinctExternalCancelled(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	options := ExternalOptions[int]{MaxInMemory: 2, TempDir: dir, Context: ctx}
+	err := DistinctExternal(externalValues(), options).ForEachErr(func(int) {
+		cancel()
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Expected temporary files to be removed, found %d", n)
+	}
+}
@@ -0,0 +1,98 @@
+package enumerable
+
+// MapIndexed maps a function over the Enumerable[T] that also receives the index of each value
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) MapIndexed(f func(int, T) T) Enumerable[T] {
+	return e.stream("MapIndexed", func(next iterator[T]) iterator[T] {
+		i := 0
+		return func() (T, bool) {
+			v, ok := next()
+			if ok {
+				v = f(i, v)
+				i++
+			}
+			return v, ok
+		}
+	})
+}
+
+// FilterIndexed filters the Enumerable[T] by a predicate that also receives the index of each value
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) FilterIndexed(f func(int, T) bool) Enumerable[T] {
+	return e.stream("FilterIndexed", func(next iterator[T]) iterator[T] {
+		i := 0
+		return func() (T, bool) {
+			for {
+				v, ok := next()
+				if !ok {
+					return v, false
+				}
+				i++
+				if f(i-1, v) {
+					return v, true
+				}
+			}
+		}
+	})
+}
+
+// TakeWhileIndexed takes the first values that satisfy a predicate that also receives the index of each value
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) TakeWhileIndexed(f func(int, T) bool) Enumerable[T] {
+	return e.stream("TakeWhileIndexed", func(next iterator[T]) iterator[T] {
+		i := 0
+		done := false
+		return func() (T, bool) {
+			if !done {
+				if v, ok := next(); ok && f(i, v) {
+					i++
+					return v, true
+				}
+				done = true
+			}
+			var zero T
+			return zero, false
+		}
+	})
+}
+
+// SkipWhileIndexed skips the first values that satisfy a predicate that also receives the index of each value
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) SkipWhileIndexed(f func(int, T) bool) Enumerable[T] {
+	return e.stream("SkipWhileIndexed", func(next iterator[T]) iterator[T] {
+		i := 0
+		skipping := true
+		return func() (T, bool) {
+			for {
+				v, ok := next()
+				if !ok || !skipping || !f(i, v) {
+					skipping = false
+					return v, ok
+				}
+				i++
+			}
+		}
+	})
+}
+
+// ForEachIndexed iterates over the Enumerable[T], calling the function with the index and value of each value
+func (e Enumerable[T]) ForEachIndexed(f func(int, T)) {
+	i := 0
+	e.ForEach(func(v T) {
+		f(i, v)
+		i++
+	})
+}
+
+// AnyIndexed returns true if a value satisfies a predicate that also receives the index of each value
+// Stops evaluating at the first value that satisfies the predicate
+func (e Enumerable[T]) AnyIndexed(f func(int, T) bool) bool {
+	_, found := e.FilterIndexed(f).First()
+	return found
+}
+
+// AllIndexed returns true if all values satisfy a predicate that also receives the index of each value
+// Stops evaluating at the first value that does not satisfy the predicate
+func (e Enumerable[T]) AllIndexed(f func(int, T) bool) bool {
+	return !e.AnyIndexed(func(i int, v T) bool { return !f(i, v) })
+}
@@ -0,0 +1,95 @@
+package enumerable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapIndexed(t *testing.T) {
+	e := New([]int{10, 20, 30})
+	result := e.MapIndexed(func(i, v int) int { return v + i }).ToList()
+	expected := []int{10, 21, 32}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestFilterIndexed(t *testing.T) {
+	e := New([]string{"a", "b", "c", "d"})
+	result := e.FilterIndexed(func(i int, _ string) bool { return i%2 == 1 }).ToList()
+	expected := []string{"b", "d"}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestIndexAfterFilter(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5})
+	result := e.Filter(func(i int) bool { return i%2 == 1 }).MapIndexed(func(i, v int) int { return i }).ToList()
+	expected := []int{0, 1, 2}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestTakeWhileIndexed(t *testing.T) {
+	e := New([]int{5, 5, 5, 5})
+	result := e.TakeWhileIndexed(func(i, _ int) bool { return i < 2 }).ToList()
+	expected := []int{5, 5}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestSkipWhileIndexed(t *testing.T) {
+	e := New([]int{1, 2, 3, 4})
+	result := e.SkipWhileIndexed(func(i, v int) bool { return i < 1 || v%2 == 0 }).ToList()
+	expected := []int{3, 4}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestForEachIndexed(t *testing.T) {
+	e := New([]string{"a", "b"})
+	result := []string{}
+	e.ForEachIndexed(func(i int, s string) {
+		result = append(result, s+string(rune('0'+i)))
+	})
+	expected := []string{"a0", "b1"}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestAllIndexed(t *testing.T) {
+	e := New([]int{0, 1, 2})
+
+	if !e.AllIndexed(func(i, v int) bool { return i == v }) {
+		t.Errorf("Expected true, got false")
+	}
+	if e.AllIndexed(func(i, v int) bool { return i < 2 }) {
+		t.Errorf("Expected false, got true")
+	}
+}
+
+func TestAnyIndexedShortCircuits(t *testing.T) {
+	calls := 0
+	e := New([]int{3, 3, 3, 3})
+
+	if !e.AnyIndexed(func(i, _ int) bool {
+		calls++
+		return i == 1
+	}) {
+		t.Errorf("Expected true, got false")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
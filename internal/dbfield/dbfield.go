@@ -0,0 +1,57 @@
+// Package dbfield maps the fields of structs to database columns
+package dbfield
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Field is an exported struct field and the column it is stored in
+type Field struct {
+	// Name is the Go name of the field
+	Name string
+	// Column is the db struct tag of the field, or the lower case field name when there is no tag
+	Column string
+	// Index is the index sequence for reflect.Value.FieldByIndex
+	Index []int
+}
+
+// Fields returns the columns of the exported fields of the struct type t, including promoted fields of embedded structs
+// Fields tagged db:"-" and fields promoted through embedded pointers are skipped
+func Fields(t reflect.Type) []Field {
+	var fields []Field
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || embedded(f) || throughPointer(t, f.Index) {
+			continue
+		}
+		column, ok := f.Tag.Lookup("db")
+		if column == "-" {
+			continue
+		}
+		if !ok || column == "" {
+			column = strings.ToLower(f.Name)
+		}
+		fields = append(fields, Field{f.Name, column, f.Index})
+	}
+	return fields
+}
+
+// embedded reports whether f is an embedded struct or pointer to a struct, whose fields are promoted instead
+func embedded(f reflect.StructField) bool {
+	t := f.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return f.Anonymous && t.Kind() == reflect.Struct
+}
+
+// throughPointer reports whether the field at index is promoted through an embedded pointer
+func throughPointer(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() == reflect.Pointer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package dbfield
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Base struct {
+	ID int `db:"id"`
+}
+
+type Extra struct {
+	Note string
+}
+
+type Record struct {
+	Base
+	*Extra
+	Name      string `db:"name"`
+	CreatedAt string
+	Secret    string `db:"-"`
+	hidden    string
+}
+
+func TestFields(t *testing.T) {
+	result := Fields(reflect.TypeFor[Record]())
+	expected := []Field{
+		{"ID", "id", []int{0, 0}},
+		{"Name", "name", []int{2}},
+		{"CreatedAt", "createdat", []int{3}},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
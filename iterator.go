@@ -0,0 +1,186 @@
+package enumerable
+
+import "sort"
+
+// iterator returns the next value and true, or the zero value and false once exhausted
+type iterator[T any] func() (T, bool)
+
+// scope holds the cleanup functions and the first error of the iterators created by one evaluation
+type scope struct {
+	closers []func()
+	err     error
+}
+
+func newScope() *scope {
+	return &scope{}
+}
+
+// onClose registers a function to release resources when the evaluation finishes
+func (s *scope) onClose(f func()) {
+	s.closers = append(s.closers, f)
+}
+
+// fail records an error that stops the evaluation, only the first error is kept
+func (s *scope) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// release runs the cleanup functions in reverse order of registration
+func (s *scope) release() {
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		s.closers[i]()
+	}
+	s.closers = nil
+}
+
+// close releases resources and panics with the error of a failed evaluation
+// Terminal operations that return an error recover the panic with recoverErr
+func (s *scope) close() {
+	s.release()
+	if s.err != nil {
+		panic(sourceError{s.err})
+	}
+}
+
+// sourceError carries the error of a failed source from where it was evaluated to the terminal operation
+type sourceError struct {
+	err error
+}
+
+func (e sourceError) Error() string {
+	return e.err.Error()
+}
+
+func (e sourceError) Unwrap() error {
+	return e.err
+}
+
+// recoverErr stores the error of a failed source in err, any other panic is propagated
+func recoverErr(err *error) {
+	if r := recover(); r != nil {
+		if e, ok := r.(sourceError); ok {
+			*err = e.err
+			return
+		}
+		panic(r)
+	}
+}
+
+// Create a new Enumerable[T] that pulls its values from a fresh iterator on each evaluation
+func fromSource[T any](source func(*scope) iterator[T]) Enumerable[T] {
+	return Enumerable[T]{source: source}
+}
+
+// iterate returns an iterator over the values of the Enumerable[T]
+// Resources held by the iterator are registered with the scope
+// Does not apply the stack, call run first
+func (e Enumerable[T]) iterate(s *scope) iterator[T] {
+	if e.source != nil {
+		return e.source(s)
+	}
+	values := e.values
+	i := 0
+	return func() (T, bool) {
+		if i >= len(values) {
+			var zero T
+			return zero, false
+		}
+		i++
+		return values[i-1], true
+	}
+}
+
+// deferred returns an iterator over values that are computed on the first pull
+func deferred[T any](compute func() []T) iterator[T] {
+	var next iterator[T]
+	return func() (T, bool) {
+		if next == nil {
+			next = New(compute()).iterate(nil)
+		}
+		return next()
+	}
+}
+
+// collect drains the source of the Enumerable[T] into its values
+func (e Enumerable[T]) collect() Enumerable[T] {
+	if e.source == nil {
+		return e
+	}
+	s := newScope()
+	defer s.close()
+	next := e.source(s)
+	values := []T{}
+	for v, ok := next(); ok; v, ok = next() {
+		values = append(values, v)
+	}
+	e.values = values
+	e.source = nil
+	return e
+}
+
+// ring is a fixed capacity queue that drops its oldest value when a value is pushed while full
+type ring[T any] struct {
+	values []T
+	start  int
+	size   int
+}
+
+func newRing[T any](capacity int) *ring[T] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &ring[T]{values: make([]T, capacity)}
+}
+
+func (r *ring[T]) full() bool {
+	return r.size == len(r.values)
+}
+
+// push adds a value to the end of the ring, overwriting the oldest value if it is full
+func (r *ring[T]) push(v T) {
+	if len(r.values) == 0 {
+		return
+	}
+	if r.full() {
+		r.values[r.start] = v
+		r.start = (r.start + 1) % len(r.values)
+		return
+	}
+	r.values[(r.start+r.size)%len(r.values)] = v
+	r.size++
+}
+
+// shift removes and returns the oldest value in the ring
+func (r *ring[T]) shift() (T, bool) {
+	var zero T
+	if r.size == 0 {
+		return zero, false
+	}
+	v := r.values[r.start]
+	r.values[r.start] = zero
+	r.start = (r.start + 1) % len(r.values)
+	r.size--
+	return v, true
+}
+
+// indexed is a value paired with its position in an Enumerable
+type indexed[T any] struct {
+	index int
+	value T
+}
+
+// sortIndexed sorts values back into the order they appeared
+func sortIndexed[T any](values []indexed[T]) {
+	sort.Slice(values, func(i, j int) bool { return values[i].index < values[j].index })
+}
+
+// unwrapIndexed returns the values without their positions
+func unwrapIndexed[T any](values []indexed[T]) []T {
+	result := make([]T, len(values))
+	for i, v := range values {
+		result[i] = v.value
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package enumerable
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Memoize returns an Enumerable[T] that evaluates the pending operations of the Enumerable[T] at most once
+// Values are cached as they are first pulled, so a terminal operation that stops early, such as Contains or First,
+// evaluates only as far as it needs and later evaluations continue from there instead of starting over
+// Every evaluation of the result, and of the operations added to it, reads the cached values in their original order,
+// including evaluations running at the same time from several goroutines
+// Operations added after Memoize are evaluated again on each evaluation, Memoize again to cache them as well
+// Resources of the source, such as the iteration of FromSeq, are held until it is exhausted or the result is
+// garbage collected
+// If the evaluation fails, the error is cached and returned by every evaluation after the values read before it
+func (e Enumerable[T]) Memoize() Enumerable[T] {
+	if e.source == nil && len(e.stack) == 0 {
+		return e
+	}
+	m := &memo[T]{source: newPuller(e)}
+	// release the resources of a partially read source once the memo is unreachable
+	runtime.AddCleanup(m, (*scope).release, m.source.scope)
+	result := fromSource(func(s *scope) iterator[T] {
+		i := 0
+		return func() (T, bool) {
+			v, ok, err := m.at(i)
+			if err != nil {
+				s.fail(err)
+			}
+			if ok {
+				i++
+			}
+			return v, ok
+		}
+	})
+	result.observer = e.observer
+	return result
+}
+
+// memo holds the values of an Enumerable[T] read so far and the evaluation to read the rest
+type memo[T any] struct {
+	mu     sync.Mutex
+	source *puller[T]
+	values []T
+	done   bool
+	err    error
+}
+
+// at returns the value at index i, pulling it from the source if it was not read yet
+// Returns false once the index is past the last value, with the error of a failed evaluation
+func (m *memo[T]) at(i int) (v T, ok bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i >= len(m.values) && !m.done {
+		if v, ok, m.err = m.source.pull(); ok {
+			m.values = append(m.values, v)
+		} else {
+			m.done = true
+		}
+	}
+	if i < len(m.values) {
+		return m.values[i], true, nil
+	}
+	return v, false, m.err
+}
+
+// puller reads the values of an Enumerable[T] one at a time for operations that share a single evaluation
+type puller[T any] struct {
+	upstream Enumerable[T]
+	next     iterator[T]
+	scope    *scope
+}
+
+func newPuller[T any](e Enumerable[T]) *puller[T] {
+	return &puller[T]{upstream: e, scope: newScope()}
+}
+
+// pull returns the next value, starting the evaluation on the first call
+// Once it returns false, with the error of a failed evaluation, the resources of the evaluation are released
+// and it must not be called again
+func (p *puller[T]) pull() (v T, ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, isSource := r.(sourceError)
+			if !isSource {
+				panic(r)
+			}
+			p.release()
+			ok, err = false, e.err
+		}
+	}()
+	if p.next == nil {
+		p.next = p.upstream.run().iterate(p.scope)
+		p.upstream = Enumerable[T]{}
+	}
+	v, ok = p.next()
+	if err = p.scope.err; err != nil || !ok {
+		p.release()
+		var zero T
+		return zero, false, err
+	}
+	return v, true, nil
+}
+
+// release releases the resources of the evaluation, which stops it early if it was not exhausted
+func (p *puller[T]) release() {
+	p.next = nil
+	p.scope.release()
+}
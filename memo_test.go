@@ -0,0 +1,155 @@
+package enumerable
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4}).Map(func(i int) int {
+		calls++
+		return i * 10
+	}).Memoize()
+
+	if !Contains(e, 20) {
+		t.Errorf("Expected %v, got %v", true, false)
+	}
+	if calls != 2 {
+		t.Errorf("Expected %v calls, got %v", 2, calls)
+	}
+	if result, expected := e.ToList(), []int{10, 20, 30, 40}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if result, expected := e.Skip(1).Take(2).ToList(), []int{20, 30}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if calls != 4 {
+		t.Errorf("Expected %v calls, got %v", 4, calls)
+	}
+}
+
+func TestMemoizeOperationsAfter(t *testing.T) {
+	before, after := 0, 0
+	e := New([]int{1, 2, 3}).Map(func(i int) int {
+		before++
+		return i
+	}).Memoize().Map(func(i int) int {
+		after++
+		return i
+	})
+	e.ToList()
+	e.ToList()
+
+	if before != 3 || after != 6 {
+		t.Errorf("Expected 3 and 6 calls, got %v and %v", before, after)
+	}
+}
+
+func TestMemoizeValuesNotShared(t *testing.T) {
+	e := New([]int{1, 2, 3}).Reverse().Memoize()
+	first := e.ToList()
+	first[0] = 100
+
+	if result, expected := e.ToList(), []int{3, 2, 1}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestMemoizeConcurrent(t *testing.T) {
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = i
+	}
+	var calls atomic.Int64
+	e := New(values).Map(func(i int) int {
+		calls.Add(1)
+		return i + 1
+	}).Memoize()
+	expected := e.Skip(0).Map(func(i int) int { return i }).ToList()
+
+	wg := sync.WaitGroup{}
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if result := e.ToList(); !reflect.DeepEqual(result, expected) {
+				t.Errorf("Expected %v values, got %v", len(expected), len(result))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1000 {
+		t.Errorf("Expected %v calls, got %v", 1000, calls.Load())
+	}
+}
+
+func TestMemoizeReleasesSource(t *testing.T) {
+	iterations, stopped := 0, false
+	e := FromSeq(func(yield func(int) bool) {
+		iterations++
+		defer func() { stopped = true }()
+		for i := range 3 {
+			if !yield(i) {
+				return
+			}
+		}
+	}).Memoize()
+
+	if v, ok := e.First(); !ok || v != 0 {
+		t.Errorf("Expected %v, got %v", 0, v)
+	}
+	if stopped {
+		t.Errorf("Expected the source to be held while partially read")
+	}
+	if result, expected := e.ToList(), []int{0, 1, 2}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if !stopped || iterations != 1 {
+		t.Errorf("Expected one stopped iteration, got %v and %v", iterations, stopped)
+	}
+}
+
+func TestMemoizeError(t *testing.T) {
+	failed := errors.New("failed")
+	calls := 0
+	e := FromSeqErr(func(yield func(int, error) bool) {
+		calls++
+		if yield(1, nil) {
+			yield(0, failed)
+		}
+	}).Memoize()
+
+	for range 2 {
+		values, err := e.ToListErr()
+		if !errors.Is(err, failed) {
+			t.Errorf("Expected %v, got %v", failed, err)
+		}
+		if !reflect.DeepEqual(values, []int(nil)) {
+			t.Errorf("Expected %v, got %v", []int(nil), values)
+		}
+	}
+	if v, ok := e.First(); !ok || v != 1 {
+		t.Errorf("Expected %v, got %v", 1, v)
+	}
+	if calls != 1 {
+		t.Errorf("Expected %v calls, got %v", 1, calls)
+	}
+}
+
+func TestMemoizeLazyError(t *testing.T) {
+	failed := errors.New("failed")
+	e := FromSeqErr(func(yield func(int, error) bool) {
+		yield(0, failed)
+	}).Reverse().Memoize()
+
+	for range 2 {
+		if _, err := e.ToListErr(); !errors.Is(err, failed) {
+			t.Errorf("Expected %v, got %v", failed, err)
+		}
+	}
+}
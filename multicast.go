@@ -0,0 +1,122 @@
+package enumerable
+
+import "cmp"
+
+// Reducer is a reduction that Multicast computes together with others in a single pass
+// Reducers are created by CountOf, SumOf, AverageOf, MinOf, MaxOf and FoldOf
+type Reducer[T any] interface {
+	reset()
+	add(v T)
+	fail(err error)
+}
+
+// Reduction is a Reducer[T] with a result of type R, read with Result after Multicast
+type Reduction[T any, R any] struct {
+	start  func()
+	step   func(T)
+	result func() (R, error)
+	err    error
+}
+
+// Result returns the result of the values of the last Multicast the Reduction was passed to
+// Returns the error of the source if the evaluation failed, and ErrEmpty from MinOf, MaxOf and AverageOf if there were no values
+func (r *Reduction[T, R]) Result() (R, error) {
+	if r.err != nil {
+		var zero R
+		return zero, r.err
+	}
+	return r.result()
+}
+
+func (r *Reduction[T, R]) reset() {
+	r.err = nil
+	r.start()
+}
+
+func (r *Reduction[T, R]) add(v T) {
+	r.step(v)
+}
+
+func (r *Reduction[T, R]) fail(err error) {
+	r.err = err
+}
+
+// Multicast evaluates the Enumerable[T] once and passes every value to each of the reducers
+// Each reducer starts from its seed, so a Reducer passed to several calls has the result of the last one
+// Returns the error of a source that failed, such as an external sort, which is also returned by the results
+func Multicast[T any](e Enumerable[T], reducers ...Reducer[T]) error {
+	for _, r := range reducers {
+		r.reset()
+	}
+	err := e.ForEachErr(func(v T) {
+		for _, r := range reducers {
+			r.add(v)
+		}
+	})
+	if err != nil {
+		for _, r := range reducers {
+			r.fail(err)
+		}
+	}
+	return err
+}
+
+// FoldOf is a Reducer[T] folding the values into an accumulator of type A starting from seed, like Fold
+func FoldOf[T any, A any](seed A, f func(A, T) A) *Reduction[T, A] {
+	return reduction(seed, f, func(a A) (A, error) { return a, nil })
+}
+
+// CountOf is a Reducer[T] counting the values, like Count
+func CountOf[T any]() *Reduction[T, int] {
+	return FoldOf(0, func(count int, _ T) int { return count + 1 })
+}
+
+// SumOf is a Reducer[T] summing the values, like Sum
+func SumOf[T Number]() *Reduction[T, T] {
+	return reduction(kahanSum[T]{}, kahanSum[T].add, func(k kahanSum[T]) (T, error) { return k.sum, nil })
+}
+
+// AverageOf is a Reducer[T] computing the mean of the values, like Average
+func AverageOf[T Number]() *Reduction[T, float64] {
+	return reduction(mean[T]{}, mean[T].add, mean[T].result)
+}
+
+// MinOf is a Reducer[T] finding the smallest value, like Min
+func MinOf[T cmp.Ordered]() *Reduction[T, T] {
+	return extremeOf(func(a, b T) bool { return cmp.Less(a, b) })
+}
+
+// MaxOf is a Reducer[T] finding the largest value, like Max
+func MaxOf[T cmp.Ordered]() *Reduction[T, T] {
+	return extremeOf(func(a, b T) bool { return cmp.Less(b, a) })
+}
+
+func extremeOf[T any](better func(T, T) bool) *Reduction[T, T] {
+	type partial struct {
+		value T
+		found bool
+	}
+	add := func(p partial, v T) partial {
+		if !p.found || better(v, p.value) {
+			return partial{v, true}
+		}
+		return p
+	}
+	result := func(p partial) (T, error) {
+		if !p.found {
+			return p.value, ErrEmpty
+		}
+		return p.value, nil
+	}
+	return reduction(partial{}, add, result)
+}
+
+// reduction is a Reduction folding into an accumulator of type A and converting it to the result
+func reduction[T any, A any, R any](seed A, f func(A, T) A, result func(A) (R, error)) *Reduction[T, R] {
+	acc := seed
+	return &Reduction[T, R]{
+		start:  func() { acc = seed },
+		step:   func(v T) { acc = f(acc, v) },
+		result: func() (R, error) { return result(acc) },
+	}
+}
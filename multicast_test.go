@@ -0,0 +1,107 @@
+package enumerable
+
+import (
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMulticast(t *testing.T) {
+	var iterations atomic.Int64
+	var stopped atomic.Bool
+	count, sum, average := CountOf[int](), SumOf[int](), AverageOf[int]()
+	minimum, maximum := MinOf[int](), MaxOf[int]()
+	odd := FoldOf(0, func(n int, v int) int { return n + v%2 })
+	err := Multicast(counted(10, &iterations, &stopped).Map(func(i int) int { return i * 3 }), count, sum, average, minimum, maximum, odd)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if iterations.Load() != 1 {
+		t.Errorf("Expected %v iterations, got %v", 1, iterations.Load())
+	}
+	if result, _ := count.Result(); result != 10 {
+		t.Errorf("Expected %v, got %v", 10, result)
+	}
+	if result, _ := sum.Result(); result != 135 {
+		t.Errorf("Expected %v, got %v", 135, result)
+	}
+	if result, _ := average.Result(); result != 13.5 {
+		t.Errorf("Expected %v, got %v", 13.5, result)
+	}
+	if result, _ := minimum.Result(); result != 0 {
+		t.Errorf("Expected %v, got %v", 0, result)
+	}
+	if result, _ := maximum.Result(); result != 27 {
+		t.Errorf("Expected %v, got %v", 27, result)
+	}
+	if result, _ := odd.Result(); result != 5 {
+		t.Errorf("Expected %v, got %v", 5, result)
+	}
+}
+
+func TestMulticastFloats(t *testing.T) {
+	values := []float64{}
+	for range 10 {
+		values = append(values, 0.1)
+	}
+	sum, average := SumOf[float64](), AverageOf[float64]()
+	Multicast(New(values), sum, average)
+
+	if result, _ := sum.Result(); result != Sum(New(values)) {
+		t.Errorf("Expected %v, got %v", Sum(New(values)), result)
+	}
+	if result, _ := average.Result(); math.Abs(result-0.1) > 1e-15 {
+		t.Errorf("Expected %v, got %v", 0.1, result)
+	}
+}
+
+func TestMulticastEmpty(t *testing.T) {
+	count, maximum, average := CountOf[int](), MaxOf[int](), AverageOf[int]()
+	Multicast(New([]int{}), count, maximum, average)
+
+	if result, err := count.Result(); result != 0 || err != nil {
+		t.Errorf("Expected %v, got %v, %v", 0, result, err)
+	}
+	if _, err := maximum.Result(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+	if _, err := average.Result(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+}
+
+func TestMulticastReset(t *testing.T) {
+	count, sum := CountOf[int](), SumOf[int]()
+	Multicast(New([]int{1, 2, 3}), count, sum)
+	Multicast(New([]int{4, 5}), count, sum)
+
+	if result, _ := count.Result(); result != 2 {
+		t.Errorf("Expected %v, got %v", 2, result)
+	}
+	if result, _ := sum.Result(); result != 9 {
+		t.Errorf("Expected %v, got %v", 9, result)
+	}
+}
+
+func TestMulticastError(t *testing.T) {
+	failed := errors.New("failed")
+	count := CountOf[int]()
+	err := Multicast(FromSeqErr(func(yield func(int, error) bool) {
+		if yield(1, nil) {
+			yield(0, failed)
+		}
+	}), count)
+
+	if !errors.Is(err, failed) {
+		t.Errorf("Expected %v, got %v", failed, err)
+	}
+	if _, err := count.Result(); !errors.Is(err, failed) {
+		t.Errorf("Expected %v, got %v", failed, err)
+	}
+	Multicast(New([]int{1}), count)
+	if result, err := count.Result(); result != 1 || err != nil {
+		t.Errorf("Expected %v, got %v, %v", 1, result, err)
+	}
+}
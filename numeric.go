@@ -0,0 +1,172 @@
+package enumerable
+
+import (
+	"cmp"
+	"math/big"
+	"math/bits"
+)
+
+// Integer is a constraint for any integer type
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Float is a constraint for any floating point type
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint for any integer or floating point type
+type Number interface {
+	Integer | Float
+}
+
+// Sum returns the sum of the values in the Enumerable[T]
+// Floating point values are summed with Kahan compensation to reduce rounding error
+// Returns 0 if the Enumerable[T] is empty
+func Sum[T Number](e Enumerable[T]) T {
+	return Fold(e, kahanSum[T]{}, kahanSum[T].add).sum
+}
+
+// Average returns the mean of the values in the Enumerable[T]
+// Integer values are summed with 128 bits of precision so the sum cannot overflow
+// Returns ErrEmpty if the Enumerable[T] is empty
+func Average[T Number](e Enumerable[T]) (float64, error) {
+	return Fold(e, mean[T]{}, mean[T].add).result()
+}
+
+// Min returns the smallest value in the Enumerable[T]
+// Returns ErrEmpty if the Enumerable[T] is empty
+func Min[T cmp.Ordered](e Enumerable[T]) (T, error) {
+	return MinBy(e, func(v T) T { return v })
+}
+
+// Max returns the largest value in the Enumerable[T]
+// Returns ErrEmpty if the Enumerable[T] is empty
+func Max[T cmp.Ordered](e Enumerable[T]) (T, error) {
+	return MaxBy(e, func(v T) T { return v })
+}
+
+// MinBy returns the first value in the Enumerable[T] with the smallest key
+// Returns ErrEmpty if the Enumerable[T] is empty
+func MinBy[T any, K cmp.Ordered](e Enumerable[T], key func(T) K) (T, error) {
+	return extremeBy(e, key, func(a, b K) bool { return cmp.Less(a, b) })
+}
+
+// MaxBy returns the first value in the Enumerable[T] with the largest key
+// Returns ErrEmpty if the Enumerable[T] is empty
+func MaxBy[T any, K cmp.Ordered](e Enumerable[T], key func(T) K) (T, error) {
+	return extremeBy(e, key, func(a, b K) bool { return cmp.Less(b, a) })
+}
+
+// Count returns the number of values in the Enumerable[T]
+func (e Enumerable[T]) Count() int {
+	e = e.run()
+	if e.source == nil {
+		return len(e.values)
+	}
+	return Fold(e, 0, func(n int, _ T) int { return n + 1 })
+}
+
+// CountWhere returns the number of values in the Enumerable[T] that satisfy the predicate
+func (e Enumerable[T]) CountWhere(f func(T) bool) int {
+	return e.Filter(f).Count()
+}
+
+// extremeBy returns the first value whose key is better than the keys of all following values
+func extremeBy[T any, K any](e Enumerable[T], key func(T) K, better func(K, K) bool) (T, error) {
+	s := newScope()
+	defer s.close()
+	next := e.run().iterate(s)
+	result, ok := next()
+	if !ok {
+		return result, ErrEmpty
+	}
+	resultKey := key(result)
+	for v, ok := next(); ok; v, ok = next() {
+		if k := key(v); better(k, resultKey) {
+			result, resultKey = v, k
+		}
+	}
+	return result, nil
+}
+
+// kahanSum is a running sum that tracks the low order bits lost when adding floating point values
+// For integer types the compensation is always zero and it behaves as a plain sum
+type kahanSum[T Number] struct {
+	sum          T
+	compensation T
+}
+
+func (k kahanSum[T]) add(v T) kahanSum[T] {
+	y := v - k.compensation
+	t := k.sum + y
+	k.compensation = (t - k.sum) - y
+	k.sum = t
+	return k
+}
+
+func (k kahanSum[T]) merge(o kahanSum[T]) kahanSum[T] {
+	return k.add(o.sum).add(-o.compensation)
+}
+
+// mean is a running sum and count used to compute an average
+// Floating point values use a Kahan sum and integer values a 128 bit two's complement sum
+type mean[T Number] struct {
+	floats kahanSum[T]
+	hi     uint64
+	lo     uint64
+	count  int
+}
+
+func (m mean[T]) add(v T) mean[T] {
+	m.count++
+	if isFloat[T]() {
+		m.floats = m.floats.add(v)
+		return m
+	}
+	var hi uint64
+	if v < 0 {
+		// sign extend negative values into the high word
+		hi = ^uint64(0)
+	}
+	return m.addWide(hi, uint64(v))
+}
+
+func (m mean[T]) addWide(hi, lo uint64) mean[T] {
+	var carry uint64
+	m.lo, carry = bits.Add64(m.lo, lo, 0)
+	m.hi, _ = bits.Add64(m.hi, hi, carry)
+	return m
+}
+
+func (m mean[T]) merge(o mean[T]) mean[T] {
+	m.floats = m.floats.merge(o.floats)
+	m = m.addWide(o.hi, o.lo)
+	m.count += o.count
+	return m
+}
+
+func (m mean[T]) result() (float64, error) {
+	if m.count == 0 {
+		return 0, ErrEmpty
+	}
+	if isFloat[T]() {
+		return float64(m.floats.sum) / float64(m.count), nil
+	}
+	sum := new(big.Int).SetUint64(m.hi)
+	sum.Lsh(sum, 64).Or(sum, new(big.Int).SetUint64(m.lo))
+	if int64(m.hi) < 0 {
+		// interpret the 128 bits as two's complement
+		sum.Sub(sum, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	result, _ := new(big.Rat).SetFrac(sum, big.NewInt(int64(m.count))).Float64()
+	return result, nil
+}
+
+// isFloat returns true if T is a floating point type
+func isFloat[T Number]() bool {
+	var one T = 1
+	return one/2 != 0
+}
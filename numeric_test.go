@@ -0,0 +1,140 @@
+package enumerable
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSumInt(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	result := Sum(e)
+
+	if result != 6 {
+		t.Errorf("Expected %d, got %d", 6, result)
+	}
+}
+
+func TestSumEmpty(t *testing.T) {
+	e := New([]int{})
+	result := Sum(e)
+
+	if result != 0 {
+		t.Errorf("Expected %d, got %d", 0, result)
+	}
+}
+
+func TestSumFloatCompensated(t *testing.T) {
+	values := []float64{1}
+	for i := 0; i < 1000; i++ {
+		values = append(values, 1e-16)
+	}
+	result := Sum(New(values))
+	expected := 1 + 1e-13
+
+	if math.Abs(result-expected) > 1e-15 {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestAverageInt(t *testing.T) {
+	e := New([]int{1, 2, 3, 4})
+	result, err := Average(e)
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if result != 2.5 {
+		t.Errorf("Expected %f, got %f", 2.5, result)
+	}
+}
+
+func TestAverageIntOverflow(t *testing.T) {
+	e := New([]int64{math.MaxInt64, math.MaxInt64, math.MaxInt64})
+	result, _ := Average(e)
+	expected := float64(math.MaxInt64)
+
+	if result != expected {
+		t.Errorf("Expected %f, got %f", expected, result)
+	}
+}
+
+func TestAverageNegative(t *testing.T) {
+	e := New([]int8{math.MinInt8, math.MinInt8, 1})
+	result, _ := Average(e)
+	expected := -255.0 / 3
+
+	if result != expected {
+		t.Errorf("Expected %f, got %f", expected, result)
+	}
+}
+
+func TestAverageUnsigned(t *testing.T) {
+	e := New([]uint64{math.MaxUint64, math.MaxUint64})
+	result, _ := Average(e)
+	expected := float64(math.MaxUint64)
+
+	if result != expected {
+		t.Errorf("Expected %f, got %f", expected, result)
+	}
+}
+
+func TestAverageFloat(t *testing.T) {
+	e := New([]float64{0.5, 1.5, 4})
+	result, _ := Average(e)
+
+	if result != 2 {
+		t.Errorf("Expected %f, got %f", 2.0, result)
+	}
+}
+
+func TestAverageEmpty(t *testing.T) {
+	e := New([]float64{})
+
+	if _, err := Average(e); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	e := New([]int{3, 1, 4, 1, 5})
+
+	if result, _ := Min(e); result != 1 {
+		t.Errorf("Expected %d, got %d", 1, result)
+	}
+	if result, _ := Max(e); result != 5 {
+		t.Errorf("Expected %d, got %d", 5, result)
+	}
+	if _, err := Min(New([]string{})); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+	if _, err := Max(New([]string{})); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	e := New([]string{"bb", "a", "ccc", "dd", "e"})
+	length := func(s string) int { return len(s) }
+
+	if result, _ := MinBy(e, length); result != "a" {
+		t.Errorf("Expected %s, got %s", "a", result)
+	}
+	if result, _ := MaxBy(e, length); result != "ccc" {
+		t.Errorf("Expected %s, got %s", "ccc", result)
+	}
+}
+
+func TestCount(t *testing.T) {
+	e := New([]int{1, 2, 3})
+
+	if result := e.Count(); result != 3 {
+		t.Errorf("Expected %d, got %d", 3, result)
+	}
+	if result := e.CountWhere(func(i int) bool { return i > 1 }); result != 2 {
+		t.Errorf("Expected %d, got %d", 2, result)
+	}
+	if result := Chunk(e, 2).Count(); result != 2 {
+		t.Errorf("Expected %d, got %d", 2, result)
+	}
+}
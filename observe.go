@@ -0,0 +1,227 @@
+package enumerable
+
+import "sync/atomic"
+
+// DefaultSampleEvery is the interval of the values passed to Observer.Element when no interval is set
+const DefaultSampleEvery = 100
+
+// StageInfo identifies an operation of an evaluation in the events passed to an Observer
+type StageInfo struct {
+	Node
+	// Index is the position of the operation in the Plan, 0 is the source
+	Index int
+	// Evaluation is unique for each evaluation in the process, stages of one evaluation share it
+	Evaluation uint64
+}
+
+// WorkerInfo identifies a worker of a parallel operation in the events passed to an Observer
+type WorkerInfo struct {
+	// Op is the parallel operation, aggregations such as SumParallel and TopKParallel are reported as FoldParallel
+	Op Op
+	// Worker is the index of the worker, from 0 to Workers - 1
+	Worker int
+	// Workers is the number of workers of the operation
+	Workers int
+	// Evaluation is unique for each parallel operation in the process, workers of one operation share it
+	Evaluation uint64
+}
+
+// Observer receives the events of evaluating an Enumerable[T] it was attached to with Observe
+// Parallel operations call WorkerStart and WorkerFinish from several goroutines, so they must be safe for concurrent use
+type Observer interface {
+	// StageStart is called when an operation is set up for an evaluation, before it produces a value
+	StageStart(stage StageInfo)
+	// Element is called with a sample of the values an operation produces, index counts its values from 0
+	Element(stage StageInfo, index int, value any)
+	// StageFinish is called once when an operation is exhausted or the evaluation stops, out is the number of values it produced
+	StageFinish(stage StageInfo, out int)
+	// Error is called with the error of the first operation that fails, before the evaluation stops
+	Error(stage StageInfo, err error)
+	// WorkerStart is called when a parallel operation starts a worker
+	WorkerStart(worker WorkerInfo)
+	// WorkerFinish is called when a worker of a parallel operation is done, items is the number of values it processed
+	WorkerFinish(worker WorkerInfo, items int)
+}
+
+// Hooks is an Observer calling the functions that are set, unset functions are skipped
+type Hooks struct {
+	OnStageStart   func(stage StageInfo)
+	OnElement      func(stage StageInfo, index int, value any)
+	OnStageFinish  func(stage StageInfo, out int)
+	OnError        func(stage StageInfo, err error)
+	OnWorkerStart  func(worker WorkerInfo)
+	OnWorkerFinish func(worker WorkerInfo, items int)
+}
+
+func (h Hooks) StageStart(stage StageInfo) {
+	if h.OnStageStart != nil {
+		h.OnStageStart(stage)
+	}
+}
+
+func (h Hooks) Element(stage StageInfo, index int, value any) {
+	if h.OnElement != nil {
+		h.OnElement(stage, index, value)
+	}
+}
+
+func (h Hooks) StageFinish(stage StageInfo, out int) {
+	if h.OnStageFinish != nil {
+		h.OnStageFinish(stage, out)
+	}
+}
+
+func (h Hooks) Error(stage StageInfo, err error) {
+	if h.OnError != nil {
+		h.OnError(stage, err)
+	}
+}
+
+func (h Hooks) WorkerStart(worker WorkerInfo) {
+	if h.OnWorkerStart != nil {
+		h.OnWorkerStart(worker)
+	}
+}
+
+func (h Hooks) WorkerFinish(worker WorkerInfo, items int) {
+	if h.OnWorkerFinish != nil {
+		h.OnWorkerFinish(worker, items)
+	}
+}
+
+// Observe attaches an Observer that receives the events of every evaluation of the Enumerable[T]
+// Element is called for every sampleEvery-th value of each operation, DefaultSampleEvery if not set and never if not positive
+// The observer is kept by the operations added later and by Apply, Transform and the parallel operations
+// Pass a nil Observer to detach it
+func (e Enumerable[T]) Observe(o Observer, sampleEvery ...int) Enumerable[T] {
+	if o == nil {
+		e.observer = nil
+		return e
+	}
+	every := DefaultSampleEvery
+	if len(sampleEvery) > 0 {
+		every = sampleEvery[0]
+	}
+	e.observer = &observer{o, every}
+	return e
+}
+
+var evaluations atomic.Uint64
+
+// observer is an Observer attached to an Enumerable[T] with its sampling interval
+type observer struct {
+	Observer
+	every int
+}
+
+// sampled returns true if the value at index is passed to Element
+func (o *observer) sampled(index int) bool {
+	return o.every > 0 && index%o.every == 0
+}
+
+// workers returns a function reporting the start of each worker of a new parallel operation
+// The function it returns reports the finish of the worker, both do nothing without an observer
+func (o *observer) workers(op Op, workers int) func(worker int) func(items int) {
+	if o == nil {
+		return func(int) func(int) { return func(int) {} }
+	}
+	evaluation := evaluations.Add(1)
+	return func(worker int) func(int) {
+		info := WorkerInfo{op, worker, workers, evaluation}
+		o.WorkerStart(info)
+		return func(items int) { o.WorkerFinish(info, items) }
+	}
+}
+
+// evaluation reports the errors of one evaluation, only the first error is reported
+type evaluation struct {
+	*observer
+	reported bool
+}
+
+func (ev *evaluation) fail(stage StageInfo, err error) {
+	if err != nil && !ev.reported {
+		ev.reported = true
+		ev.Error(stage, err)
+	}
+}
+
+// report reports the error of a failed source carried by a panic and propagates the panic
+func (ev *evaluation) report(stage StageInfo) {
+	if r := recover(); r != nil {
+		if e, ok := r.(sourceError); ok {
+			ev.fail(stage, e.err)
+		}
+		panic(r)
+	}
+}
+
+// observed applies the functions from the stack like run, reporting each operation to the observer
+func (e Enumerable[T]) observed() Enumerable[T] {
+	ev := &evaluation{observer: e.observer}
+	id := evaluations.Add(1)
+	plan := e.Plan()
+	stack := e.stack
+	e.stack = nil
+	e = observeStage(ev, StageInfo{plan[0], 0, id}, e)
+	for i, s := range stack {
+		info := StageInfo{plan[i+1], i + 1, id}
+		e = observeStage(ev, info, applyObserved(ev, info, s, e))
+	}
+	return e
+}
+
+func applyObserved[T any](ev *evaluation, info StageInfo, s stage[T], e Enumerable[T]) Enumerable[T] {
+	defer ev.report(info)
+	return s.apply(e)
+}
+
+// observeStage reports the start of the operation producing the values of the Enumerable[T] and the values pulled from it
+func observeStage[T any](ev *evaluation, info StageInfo, e Enumerable[T]) Enumerable[T] {
+	ev.StageStart(info)
+	return fromSource(func(s *scope) iterator[T] {
+		out := 0
+		finished := false
+		finish := func() {
+			if !finished {
+				finished = true
+				ev.StageFinish(info, out)
+			}
+		}
+		next := iterateObserved(ev, info, s, e)
+		// registered after the operations it pulls from so stages finish from the last when the evaluation stops
+		if s != nil {
+			s.onClose(finish)
+		}
+		return func() (T, bool) {
+			v, ok := pullObserved(ev, info, s, next)
+			if !ok {
+				finish()
+				return v, false
+			}
+			if ev.sampled(out) {
+				ev.Element(info, out, v)
+			}
+			out++
+			return v, true
+		}
+	})
+}
+
+func iterateObserved[T any](ev *evaluation, info StageInfo, s *scope, e Enumerable[T]) iterator[T] {
+	defer ev.report(info)
+	next := e.iterate(s)
+	if s != nil {
+		ev.fail(info, s.err)
+	}
+	return next
+}
+
+func pullObserved[T any](ev *evaluation, info StageInfo, s *scope, next iterator[T]) (T, bool) {
+	defer ev.report(info)
+	v, ok := next()
+	if s != nil {
+		ev.fail(info, s.err)
+	}
+	return v, ok
+}
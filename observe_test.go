@@ -0,0 +1,310 @@
+package enumerable
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/sdehm/go-enumerable/expr"
+)
+
+// summary returns the kind, stage and index or count of the stage events
+func summary(events []Event) []string {
+	result := []string{}
+	for _, e := range events {
+		switch e.Kind {
+		case EventStageStart:
+			result = append(result, fmt.Sprintf("start %d %v", e.Stage.Index, e.Stage.Node))
+		case EventElement:
+			result = append(result, fmt.Sprintf("element %d %d %v", e.Stage.Index, e.Index, e.Value))
+		case EventStageFinish:
+			result = append(result, fmt.Sprintf("finish %d %d", e.Stage.Index, e.Count))
+		case EventError:
+			result = append(result, fmt.Sprintf("error %d %v", e.Stage.Index, e.Err))
+		}
+	}
+	return result
+}
+
+func TestObserve(t *testing.T) {
+	recorder := &Recorder{}
+	result := New([]int{1, 2, 3}).
+		Map(func(i int) int { return i * 2 }).
+		Filter(func(i int) bool { return i > 2 }).
+		Observe(recorder, 1).
+		ToList()
+	expected := []string{
+		"start 0 Values(3)",
+		"start 1 Map",
+		"start 2 Filter",
+		"element 0 0 1",
+		"element 1 0 2",
+		"element 0 1 2",
+		"element 1 1 4",
+		"element 2 0 4",
+		"element 0 2 3",
+		"element 1 2 6",
+		"element 2 1 6",
+		"finish 0 3",
+		"finish 1 3",
+		"finish 2 2",
+	}
+
+	if !reflect.DeepEqual(result, []int{4, 6}) {
+		t.Errorf("Expected %v, got %v", []int{4, 6}, result)
+	}
+	if events := summary(recorder.Events()); !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %v", expected, events)
+	}
+}
+
+func TestObserveEvaluations(t *testing.T) {
+	recorder := &Recorder{}
+	e := New([]int{1, 2}).Map(func(i int) int { return i }).Observe(recorder)
+	e.ToList()
+	e.ToList()
+	evaluations := map[uint64]int{}
+	for _, event := range recorder.Events() {
+		evaluations[event.Stage.Evaluation]++
+	}
+
+	if len(evaluations) != 2 {
+		t.Errorf("Expected 2 evaluations, got %v", evaluations)
+	}
+	recorder.Reset()
+	if events := recorder.Events(); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+}
+
+func TestObserveSampling(t *testing.T) {
+	values := make([]int, 250)
+	tests := []struct {
+		name        string
+		sampleEvery []int
+		expected    []int
+	}{
+		{"default", nil, []int{0, 100, 200}},
+		{"every 60", []int{60}, []int{0, 60, 120, 180, 240}},
+		{"never", []int{0}, []int{}},
+		{"negative", []int{-1}, []int{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := &Recorder{}
+			New(values).Observe(recorder, test.sampleEvery...).ToList()
+			result := []int{}
+			for _, e := range recorder.Events() {
+				if e.Kind == EventElement {
+					result = append(result, e.Index)
+				}
+			}
+
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestObserveStopsEarly(t *testing.T) {
+	recorder := &Recorder{}
+	New([]int{1, 2, 3, 4, 5}).Map(func(i int) int { return i }).Take(2).Observe(recorder, 0).ToList()
+	expected := []string{
+		"start 0 Values(5)",
+		"start 1 Map",
+		"start 2 Take(2)",
+		"finish 2 2",
+		"finish 1 2",
+		"finish 0 2",
+	}
+
+	if events := summary(recorder.Events()); !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %v", expected, events)
+	}
+}
+
+func TestObserveError(t *testing.T) {
+	type person struct{ Name string }
+	recorder := &Recorder{}
+	_, err := New([]person{{"a"}, {"b"}}).
+		Map(func(p person) person { return p }).
+		FilterExpr(expr.Field("Age").Gt(3)).
+		OrderBy(func(a, b person) int { return cmp.Compare(a.Name, b.Name) }).
+		Observe(recorder, 0).
+		ToListErr()
+	errs := []string{}
+	for _, e := range recorder.Events() {
+		if e.Kind == EventError {
+			errs = append(errs, fmt.Sprintf("%d %v", e.Stage.Index, e.Stage.Op))
+			if !errors.Is(e.Err, err) {
+				t.Errorf("Expected %v, got %v", err, e.Err)
+			}
+		}
+	}
+
+	if !errors.Is(err, expr.ErrType) {
+		t.Errorf("Expected %v, got %v", expr.ErrType, err)
+	}
+	if expected := []string{"2 FilterExpr"}; !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected %v, got %v", expected, errs)
+	}
+}
+
+func TestObserveSourceError(t *testing.T) {
+	failed := errors.New("failed")
+	recorder := &Recorder{}
+	_, err := TransformErr(New([]int{1, 2, 3}), func(i int) (int, error) {
+		if i == 2 {
+			return 0, failed
+		}
+		return i, nil
+	}).Reverse().Observe(recorder, 0).ToListErr()
+	expected := []string{
+		"start 0 Source",
+		"error 0 failed",
+		"finish 0 1",
+	}
+
+	if !errors.Is(err, failed) {
+		t.Errorf("Expected %v, got %v", failed, err)
+	}
+	if events := summary(recorder.Events()); !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %v", expected, events)
+	}
+}
+
+func TestObserveKeptByApply(t *testing.T) {
+	recorder := &Recorder{}
+	e := New([]int{1, 2, 3}).Observe(recorder).Map(func(i int) int { return i + 1 }).Apply()
+	recorder.Reset()
+	e.Filter(func(i int) bool { return i > 2 }).ToList()
+
+	if events := summary(recorder.Events()); len(events) == 0 || events[0] != "start 0 Values(3)" {
+		t.Errorf("Expected the applied values to be observed, got %v", events)
+	}
+	recorder.Reset()
+	e.Observe(nil).ToList()
+	if events := recorder.Events(); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+}
+
+func TestObserveWorkers(t *testing.T) {
+	recorder := &Recorder{}
+	values := make([]int, 100)
+	New(values).Observe(recorder).MapParallel(func(i int) int { return i }, 3)
+	SumParallel(New(values).Observe(recorder), 2)
+	starts := map[Op]int{}
+	items := map[Op]int{}
+	for _, e := range recorder.Events() {
+		switch e.Kind {
+		case EventWorkerStart:
+			starts[e.Worker.Op]++
+		case EventWorkerFinish:
+			items[e.Worker.Op] += e.Count
+		}
+	}
+
+	if expected := map[Op]int{"MapParallel": 3, "FoldParallel": 2}; !reflect.DeepEqual(starts, expected) {
+		t.Errorf("Expected %v, got %v", expected, starts)
+	}
+	if expected := map[Op]int{"MapParallel": 100, "FoldParallel": 100}; !reflect.DeepEqual(items, expected) {
+		t.Errorf("Expected %v, got %v", expected, items)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	finished := []int{}
+	hooks := Hooks{OnStageFinish: func(stage StageInfo, out int) { finished = append(finished, out) }}
+	New([]int{1, 2, 3, 4}).Filter(func(i int) bool { return i%2 == 0 }).Observe(hooks).ForEach(func(int) {})
+
+	if expected := []int{4, 2}; !reflect.DeepEqual(finished, expected) {
+		t.Errorf("Expected %v, got %v", expected, finished)
+	}
+}
+
+type fakeSpan struct {
+	name       string
+	attributes map[string]any
+	events     []string
+	errs       []error
+	ended      bool
+}
+
+func (s *fakeSpan) SetAttributes(attributes ...Attribute) {
+	for _, a := range attributes {
+		s.attributes[a.Key] = a.Value
+	}
+}
+
+func (s *fakeSpan) AddEvent(name string, attributes ...Attribute) {
+	s.events = append(s.events, name+fmt.Sprint(attributes))
+}
+
+func (s *fakeSpan) RecordError(err error) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *fakeSpan) End() {
+	s.ended = true
+}
+
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &fakeSpan{name: name, attributes: map[string]any{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestTraceObserver(t *testing.T) {
+	tracer := &fakeTracer{}
+	observer := TraceObserver(context.Background(), tracer)
+	New([]int{1, 2, 3}).Take(2).Observe(observer, 2).MapParallel(func(i int) int { return i }, 2)
+
+	if len(tracer.spans) != 4 {
+		t.Fatalf("Expected 4 spans, got %v", len(tracer.spans))
+	}
+	values, take := tracer.spans[0], tracer.spans[1]
+	if values.name != "Values" || take.name != "Take" {
+		t.Errorf("Expected Values and Take, got %v and %v", values.name, take.name)
+	}
+	if take.attributes["enumerable.stage"] != 1 || take.attributes["enumerable.out"] != 2 || !take.ended {
+		t.Errorf("Expected an ended span of stage 1 with 2 values, got %v", take.attributes)
+	}
+	if expected := []string{"element[{enumerable.index 0}]"}; !reflect.DeepEqual(take.events, expected) {
+		t.Errorf("Expected %v, got %v", expected, take.events)
+	}
+	items := 0
+	for _, worker := range tracer.spans[2:] {
+		if worker.name != "MapParallel.worker" || worker.attributes["enumerable.workers"] != 2 || !worker.ended {
+			t.Errorf("Expected an ended worker span, got %v %v", worker.name, worker.attributes)
+		}
+		items += worker.attributes["enumerable.items"].(int)
+	}
+	if items != 2 {
+		t.Errorf("Expected %v, got %v", 2, items)
+	}
+}
+
+func TestTraceObserverError(t *testing.T) {
+	failed := errors.New("failed")
+	tracer := &fakeTracer{}
+	TransformErr(New([]int{1}), func(i int) (int, error) { return 0, failed }).
+		Observe(TraceObserver(context.Background(), tracer)).
+		ToListErr()
+
+	if len(tracer.spans) != 1 || !reflect.DeepEqual(tracer.spans[0].errs, []error{failed}) || !tracer.spans[0].ended {
+		t.Errorf("Expected an ended span recording %v, got %v", failed, tracer.spans)
+	}
+}
@@ -1,117 +1,292 @@
 package enumerable
 
 import (
+	"cmp"
 	"runtime"
 	"sync"
 )
 
+// ForEachParallel calls f for each value of the Enumerable[T] on numWorkers goroutines, GOMAXPROCS by default
+// Panics if a source fails, use ForEachParallelErr for sources that can fail
 func (e Enumerable[T]) ForEachParallel(f func(T), numWorkers ...int) {
 	// set number of workers to GOMAXPROCS by default
 	workers := setNumWorkers(numWorkers...)
-	jobs := buildJobQueue(e)
-	results := make(chan struct{}, len(e.values))
+	report := e.observer.workers("ForEachParallel", workers)
+	jobs := make(chan workItem[T], workers)
 
-	workerFunc := func(j workItem[T]) {
+	workerFunc := func(_ int, j workItem[T]) {
 		f(j.value)
 	}
 
 	wg := sync.WaitGroup{}
-	startWorkers(jobs, &wg, workerFunc, workers)
-
-	// wait for all workers to finish
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	startWorkers(jobs, &wg, workerFunc, workers, report)
+	defer wg.Wait()
+	queueJobs(e, jobs)
+}
 
-	// wait for all results to be processed
-	<-results
+// ForEachParallelErr calls f for each value of the Enumerable[T] on numWorkers goroutines like ForEachParallel
+// Returns the error of a source that failed, once the workers have finished the values read before it
+func (e Enumerable[T]) ForEachParallelErr(f func(T), numWorkers ...int) (err error) {
+	defer recoverErr(&err)
+	e.ForEachParallel(f, numWorkers...)
+	return nil
 }
 
+// MapParallel maps f over the Enumerable[T] on numWorkers goroutines, GOMAXPROCS by default, keeping the order
+// The Enumerable[T] is evaluated immediately, the error of a failed source is returned by ToListErr and ForEachErr
 func (e Enumerable[T]) MapParallel(f func(T) T, numWorkers ...int) Enumerable[T] {
+	return transformParallel(e, "MapParallel", f, numWorkers...)
+}
+
+// TransformParallel maps f over the Enumerable[T] on numWorkers goroutines like MapParallel, returning an
+// Enumerable of a different type
+// The Enumerable[T] is evaluated immediately, the error of a failed source is returned by ToListErr and ForEachErr
+func TransformParallel[T any, U any](e Enumerable[T], f func(T) U, numWorkers ...int) Enumerable[U] {
+	return transformParallel(e, "TransformParallel", f, numWorkers...)
+}
+
+func transformParallel[T any, U any](e Enumerable[T], op Op, f func(T) U, numWorkers ...int) Enumerable[U] {
+	values, err := collectParallel(e, op, f, numWorkers...)
+	if err != nil {
+		return failed[U](err)
+	}
+	result := New(values)
+	result.observer = e.observer
+	return result
+}
+
+// collectParallel returns the results of f for each value in order, or the error of a failed source
+func collectParallel[T any, U any](e Enumerable[T], op Op, f func(T) U, numWorkers ...int) (values []U, err error) {
+	defer recoverErr(&err)
 	workers := setNumWorkers(numWorkers...)
-	jobs := buildJobQueue(e)
-	results := make(chan workItem[T], len(e.values))
+	report := e.observer.workers(op, workers)
+	jobs := make(chan workItem[T], workers)
+	results := make(chan workItem[U], workers)
 
-	workerFunc := func(j workItem[T]) {
-		results <- workItem[T]{f(j.value), j.index}
+	workerFunc := func(_ int, j workItem[T]) {
+		results <- workItem[U]{f(j.value), j.index}
 	}
 
 	wg := sync.WaitGroup{}
-	startWorkers(jobs, &wg, workerFunc, workers)
+	startWorkers(jobs, &wg, workerFunc, workers, report)
+	collected := collectResults(results, &wg)
+	queueJobs(e, jobs)
+	return <-collected, nil
+}
 
-	// wait for all workers to finish
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+// failed returns an Enumerable[T] whose evaluations fail with err
+func failed[T any](err error) Enumerable[T] {
+	return fromSource(func(s *scope) iterator[T] {
+		s.fail(err)
+		return emptyIterator[T]
+	})
+}
+
+// SumParallel returns the sum of the values in the Enumerable[T] using a partial sum per worker
+// Panics if a source fails, use SumParallelErr for sources that can fail
+func SumParallel[T Number](e Enumerable[T], numWorkers ...int) T {
+	return foldParallel(e, kahanSum[T]{}, kahanSum[T].add, kahanSum[T].merge, numWorkers...).sum
+}
+
+// SumParallelErr returns the sum of the values in the Enumerable[T] like SumParallel
+// Returns the error of a source that failed
+func SumParallelErr[T Number](e Enumerable[T], numWorkers ...int) (sum T, err error) {
+	defer recoverErr(&err)
+	return SumParallel(e, numWorkers...), nil
+}
+
+// AverageParallel returns the mean of the values in the Enumerable[T] using a partial sum per worker
+// Returns ErrEmpty if the Enumerable[T] is empty or the error of a source that failed
+func AverageParallel[T Number](e Enumerable[T], numWorkers ...int) (average float64, err error) {
+	defer recoverErr(&err)
+	return foldParallel(e, mean[T]{}, mean[T].add, mean[T].merge, numWorkers...).result()
+}
+
+// MinParallel returns the smallest value in the Enumerable[T] using a partial minimum per worker
+// Returns ErrEmpty if the Enumerable[T] is empty or the error of a source that failed
+func MinParallel[T cmp.Ordered](e Enumerable[T], numWorkers ...int) (T, error) {
+	return extremeParallel(e, func(a, b T) bool { return cmp.Less(a, b) }, numWorkers...)
+}
+
+// MaxParallel returns the largest value in the Enumerable[T] using a partial maximum per worker
+// Returns ErrEmpty if the Enumerable[T] is empty or the error of a source that failed
+func MaxParallel[T cmp.Ordered](e Enumerable[T], numWorkers ...int) (T, error) {
+	return extremeParallel(e, func(a, b T) bool { return cmp.Less(b, a) }, numWorkers...)
+}
+
+// TopKParallel returns the k largest values of the Enumerable[T] by cmp, largest first
+// Each worker keeps a heap of its k largest values and the heaps are merged at the end
+// Equal values keep their original order
+// The Enumerable[T] is evaluated immediately, the error of a failed source is returned by ToListErr and ForEachErr
+func (e Enumerable[T]) TopKParallel(k int, cmp func(a, b T) int, numWorkers ...int) Enumerable[T] {
+	add := func(h topK[T], i int, v T) topK[T] {
+		h.add(indexed[T]{i, v})
+		return h
+	}
+	merge := func(h topK[T], o topK[T]) topK[T] {
+		h.merge(o)
+		return h
+	}
+	seed := func() topK[T] { return topK[T]{k: k, cmp: cmp} }
+	result, err := foldParallelErr(e, seed, add, merge, numWorkers...)
+	if err != nil {
+		return failed[T](err)
+	}
+	return New(unwrapIndexed(result.sorted()))
+}
+
+// BottomKParallel returns the k smallest values of the Enumerable[T] by cmp, smallest first
+// Each worker keeps a heap of its k smallest values and the heaps are merged at the end
+// Equal values keep their original order
+// The Enumerable[T] is evaluated immediately, the error of a failed source is returned by ToListErr and ForEachErr
+func (e Enumerable[T]) BottomKParallel(k int, cmp func(a, b T) int, numWorkers ...int) Enumerable[T] {
+	return e.TopKParallel(k, reverseCmp(cmp), numWorkers...)
+}
 
-	for r := range results {
-		e.values[r.index] = r.value
+func extremeParallel[T any](e Enumerable[T], better func(T, T) bool, numWorkers ...int) (value T, err error) {
+	defer recoverErr(&err)
+	type partial struct {
+		value T
+		found bool
+	}
+	add := func(p partial, v T) partial {
+		if !p.found || better(v, p.value) {
+			return partial{v, true}
+		}
+		return p
+	}
+	merge := func(p partial, o partial) partial {
+		if !o.found {
+			return p
+		}
+		return add(p, o.value)
 	}
+	result := foldParallel(e, partial{}, add, merge, numWorkers...)
+	if !result.found {
+		return result.value, ErrEmpty
+	}
+	return result.value, nil
+}
+
+// FoldParallel folds the values of the Enumerable[T] into one accumulator per worker and merges the accumulators
+// seed is called once per worker and once for the merged result, so accumulators holding pointers or maps are not shared
+// Values are not folded in order so f and merge must not depend on it
+// Panics if a source fails, use FoldParallelErr for sources that can fail
+func FoldParallel[T any, A any](e Enumerable[T], seed func() A, f func(A, T) A, merge func(A, A) A, numWorkers ...int) A {
+	return foldParallelIndexed(e, seed, func(a A, _ int, v T) A { return f(a, v) }, merge, numWorkers...)
+}
+
+// FoldParallelErr folds the values of the Enumerable[T] into one accumulator per worker like FoldParallel
+// Returns the error of a source that failed
+func FoldParallelErr[T any, A any](e Enumerable[T], seed func() A, f func(A, T) A, merge func(A, A) A, numWorkers ...int) (A, error) {
+	return foldParallelErr(e, seed, func(a A, _ int, v T) A { return f(a, v) }, merge, numWorkers...)
+}
+
+// foldParallelErr is foldParallelIndexed returning the error of a source that failed
+func foldParallelErr[T any, A any](e Enumerable[T], seed func() A, f func(A, int, T) A, merge func(A, A) A, numWorkers ...int) (result A, err error) {
+	defer recoverErr(&err)
+	return foldParallelIndexed(e, seed, f, merge, numWorkers...), nil
+}
 
-	return e
+// foldParallel is FoldParallel with an accumulator that can be copied by value
+func foldParallel[T any, A any](e Enumerable[T], seed A, f func(A, T) A, merge func(A, A) A, numWorkers ...int) A {
+	return FoldParallel(e, func() A { return seed }, f, merge, numWorkers...)
 }
 
-func TransformParallel[T comparable, U comparable](e Enumerable[T], f func(T) U, numWorkers ...int) Enumerable[U] {
-	result := New(make([]U, len(e.values)))
+// foldParallelIndexed is FoldParallel with the index of each value passed to f
+func foldParallelIndexed[T any, A any](e Enumerable[T], seed func() A, f func(A, int, T) A, merge func(A, A) A, numWorkers ...int) A {
 	workers := setNumWorkers(numWorkers...)
-	jobs := buildJobQueue(e)
-	results := make(chan workItem[U], len(e.values))
+	report := e.observer.workers("FoldParallel", workers)
+	jobs := make(chan workItem[T], workers)
+	accumulators := make([]A, workers)
+	for i := range accumulators {
+		accumulators[i] = seed()
+	}
 
-	workerFunc := func(j workItem[T]) {
-		results <- workItem[U]{f(j.value), j.index}
+	workerFunc := func(worker int, j workItem[T]) {
+		accumulators[worker] = f(accumulators[worker], j.index, j.value)
 	}
 
 	wg := sync.WaitGroup{}
-	startWorkers(jobs, &wg, workerFunc, workers)
-
-	// wait for all workers to finish
-	go func() {
-		wg.Wait()
-		close(results)
+	startWorkers(jobs, &wg, workerFunc, workers, report)
+	func() {
+		// wait for the workers to finish even if the source fails, so no worker uses an accumulator afterwards
+		defer wg.Wait()
+		queueJobs(e, jobs)
 	}()
 
-	for r := range results {
-		result.values[r.index] = r.value
+	result := seed()
+	for _, acc := range accumulators {
+		result = merge(result, acc)
 	}
-
 	return result
 }
 
-type workItem[T comparable] struct {
+type workItem[T any] struct {
 	value T
 	index int
 }
 
+// setNumWorkers returns the number of workers given, at least one, or GOMAXPROCS if none is given
+// Values are queued until a worker takes them, so without a worker the queue would never drain
 func setNumWorkers(numWorkers ...int) int {
 	if len(numWorkers) > 0 {
-		return numWorkers[0]
+		return max(numWorkers[0], 1)
 	}
 	// set number of workers to GOMAXPROCS by default
 	return runtime.GOMAXPROCS(0)
 }
 
-func buildJobQueue[T comparable](e Enumerable[T]) chan workItem[T] {
-	jobs := make(chan workItem[T], len(e.values))
-	// populate jobs channel
+// queueJobs evaluates the Enumerable[T] and sends its values to the workers, then closes jobs
+// Values are read as the workers take them, so only the values in the buffer of jobs are held at once
+// Panics with the error of a failed source once the values read so far are queued
+func queueJobs[T any](e Enumerable[T], jobs chan<- workItem[T]) {
+	s := newScope()
+	defer s.close()
+	defer close(jobs)
+	next := e.run().iterate(s)
+	for i := 0; ; i++ {
+		v, ok := next()
+		if !ok {
+			return
+		}
+		jobs <- workItem[T]{v, i}
+	}
+}
+
+// collectResults closes results once the workers finish and returns a channel receiving the results by index
+func collectResults[T any](results chan workItem[T], wg *sync.WaitGroup) <-chan []T {
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	collected := make(chan []T, 1)
 	go func() {
-		for i, v := range e.values {
-			jobs <- workItem[T]{v, i}
+		values := []T{}
+		for r := range results {
+			if r.index >= len(values) {
+				values = append(values, make([]T, r.index+1-len(values))...)
+			}
+			values[r.index] = r.value
 		}
-		close(jobs)
+		collected <- values
 	}()
-	return jobs
+	return collected
 }
 
-func startWorkers[T comparable](jobs chan workItem[T], wg *sync.WaitGroup, f func(workItem[T]), workers int) {
+// startWorkers starts workers calling f with their number and each job until jobs is closed
+func startWorkers[T any](jobs chan workItem[T], wg *sync.WaitGroup, f func(int, workItem[T]), workers int, report func(worker int) func(items int)) {
 	// start workers
 	for i := 0; i < workers; i++ {
 		wg.Add(1)
 		go func() {
+			finish := report(i)
+			items := 0
 			for j := range jobs {
-				f(j)
+				f(i, j)
+				items++
 			}
+			finish(items)
 			wg.Done()
 		}()
 	}
@@ -1,6 +1,10 @@
 package enumerable
 
 import (
+	"cmp"
+	"errors"
+	"math"
+	"reflect"
 	"strconv"
 	"sync/atomic"
 	"testing"
@@ -65,4 +69,199 @@ func TestSetNumWorkers(t *testing.T) {
 	if workers != 1 {
 		t.Errorf("Expected 1, got %d", workers)
 	}
+	if workers := setNumWorkers(0); workers != 1 {
+		t.Errorf("Expected 1, got %d", workers)
+	}
+}
+
+func TestSumParallel(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5})
+	result := SumParallel(e, 2)
+
+	if result != 15 {
+		t.Errorf("Expected %d, got %d", 15, result)
+	}
+}
+
+func TestAverageParallel(t *testing.T) {
+	e := New([]int64{math.MaxInt64, math.MaxInt64, math.MinInt64, math.MinInt64})
+	result, err := AverageParallel(e, 3)
+	expected := -0.5
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if result != expected {
+		t.Errorf("Expected %f, got %f", expected, result)
+	}
+	if _, err := AverageParallel(New([]int{})); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+}
+
+func TestMinMaxParallel(t *testing.T) {
+	e := New([]int{3, 1, 4, 1, 5, 9, 2, 6})
+
+	if result, _ := MinParallel(e, 3); result != 1 {
+		t.Errorf("Expected %d, got %d", 1, result)
+	}
+	if result, _ := MaxParallel(e, 3); result != 9 {
+		t.Errorf("Expected %d, got %d", 9, result)
+	}
+	if _, err := MaxParallel(New([]int{}), 3); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got %v", ErrEmpty, err)
+	}
+}
+
+func TestTopKParallel(t *testing.T) {
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = (i * 7919) % 1000
+	}
+	e := New(values)
+	result := e.TopKParallel(5, cmp.Compare[int], 4).ToList()
+	expected := []int{999, 998, 997, 996, 995}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestBottomKParallelStable(t *testing.T) {
+	type item struct{ key, index int }
+	values := make([]item, 100)
+	for i := range values {
+		values[i] = item{i % 3, i}
+	}
+	result := New(values).BottomKParallel(3, func(a, b item) int { return cmp.Compare(a.key, b.key) }, 4).ToList()
+	expected := []item{{0, 0}, {0, 3}, {0, 6}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestFoldParallel(t *testing.T) {
+	e := New([]string{"a", "b", "a", "c", "a"})
+	seed := func() map[string]int { return map[string]int{} }
+	add := func(m map[string]int, s string) map[string]int {
+		m[s]++
+		return m
+	}
+	merge := func(m map[string]int, o map[string]int) map[string]int {
+		for k, v := range o {
+			m[k] += v
+		}
+		return m
+	}
+	result := FoldParallel(e, seed, add, merge, 3)
+	expected := map[string]int{"a": 3, "b": 1, "c": 1}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestMapParallelDoesNotMutate(t *testing.T) {
+	values := []int{1, 2, 3}
+	result := New(values).MapParallel(func(i int) int { return i * 2 }).ToList()
+
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("Expected %v, got %v", []int{1, 2, 3}, values)
+	}
+	if !reflect.DeepEqual(result, []int{2, 4, 6}) {
+		t.Errorf("Expected %v, got %v", []int{2, 4, 6}, result)
+	}
+}
+
+func TestParallelReadsValuesAsWorkersTakeThem(t *testing.T) {
+	const workers = 2
+	var pulled, processed atomic.Int64
+	ahead := 0
+	source := func() Enumerable[int] {
+		pulled.Store(0)
+		processed.Store(0)
+		ahead = 0
+		return FromSeq(func(yield func(int) bool) {
+			for i := range 1000 {
+				ahead = max(ahead, int(pulled.Add(1)-processed.Load()))
+				if !yield(i) {
+					return
+				}
+			}
+		})
+	}
+	// a value is being worked on by each worker, waiting in the queue for each worker, being sent and being read
+	limit := 2*workers + 2
+
+	source().ForEachParallel(func(int) { processed.Add(1) }, workers)
+	if ahead > limit {
+		t.Errorf("Expected at most %d values read ahead by ForEachParallel, got %d", limit, ahead)
+	}
+	sum := func(a, b int) int { return a + b }
+	FoldParallel(source(), func() int { return 0 }, func(a, v int) int {
+		processed.Add(1)
+		return a + v
+	}, sum, workers)
+	if ahead > limit {
+		t.Errorf("Expected at most %d values read ahead by FoldParallel, got %d", limit, ahead)
+	}
+}
+
+func TestForEachParallelSourceError(t *testing.T) {
+	failed := errors.New("failed")
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || !errors.Is(err, failed) {
+			t.Errorf("Expected panic with %v, got %v", failed, r)
+		}
+	}()
+	FromSeqErr(func(yield func(int, error) bool) {
+		if yield(1, nil) {
+			yield(0, failed)
+		}
+	}).ForEachParallel(func(int) {}, 2)
+}
+
+func TestParallelErrSourceError(t *testing.T) {
+	failure := errors.New("failed")
+	source := FromSeqErr(func(yield func(int, error) bool) {
+		if yield(1, nil) {
+			yield(0, failure)
+		}
+	})
+	if err := source.ForEachParallelErr(func(int) {}, 2); !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+	seed := func() int { return 0 }
+	add := func(a, v int) int { return a + v }
+	if _, err := FoldParallelErr(source, seed, add, add, 2); !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+	if _, err := SumParallelErr(source, 2); !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+	if _, err := AverageParallel(source, 2); !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+	if _, err := MinParallel(source, 2); !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+	if _, err := source.MapParallel(func(i int) int { return i }, 2).ToListErr(); !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+	if _, err := source.TopKParallel(1, cmp.Compare[int], 2).ToListErr(); !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+}
+
+func TestParallelErrNoError(t *testing.T) {
+	values := New([]int{1, 2, 3})
+	sum, err := FoldParallelErr(values, func() int { return 0 }, func(a, v int) int { return a + v }, func(a, b int) int { return a + b }, 2)
+	if err != nil || sum != 6 {
+		t.Errorf("Expected %v, got %v, %v", 6, sum, err)
+	}
+	if err := values.ForEachParallelErr(func(int) {}, 2); err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
 }
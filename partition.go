@@ -0,0 +1,74 @@
+package enumerable
+
+import "sync"
+
+// Partition splits the Enumerable[T] into the values that satisfy a predicate and the values that do not
+// The Enumerable[T] is evaluated once when either result is first used and both results share the buffered values
+func (e Enumerable[T]) Partition(f func(T) bool) (Enumerable[T], Enumerable[T]) {
+	return e.split(func(values []T) ([]T, []T) {
+		matched, rest := []T{}, []T{}
+		for _, v := range values {
+			if f(v) {
+				matched = append(matched, v)
+			} else {
+				rest = append(rest, v)
+			}
+		}
+		return matched, rest
+	})
+}
+
+// SplitAt splits the Enumerable[T] into the first n values and the remaining values
+// If n is greater than the length of the Enumerable[T], the second result is empty
+// If n is negative, the first result is empty
+// The Enumerable[T] is evaluated once when either result is first used and both results share the buffered values
+func (e Enumerable[T]) SplitAt(n int) (Enumerable[T], Enumerable[T]) {
+	return e.split(func(values []T) ([]T, []T) {
+		i := n
+		if i < 0 {
+			i = 0
+		}
+		if i > len(values) {
+			i = len(values)
+		}
+		return values[:i:i], values[i:]
+	})
+}
+
+// Span splits the Enumerable[T] into the longest prefix of values that satisfy a predicate and the remaining values
+// The Enumerable[T] is evaluated once when either result is first used and both results share the buffered values
+func (e Enumerable[T]) Span(f func(T) bool) (Enumerable[T], Enumerable[T]) {
+	return e.split(func(values []T) ([]T, []T) {
+		n := 0
+		for n < len(values) && f(values[n]) {
+			n++
+		}
+		return values[:n:n], values[n:]
+	})
+}
+
+// split evaluates the Enumerable[T] at most once and divides its values between two Enumerables
+// The error of a failed source is returned by the evaluations of both Enumerables
+func (e Enumerable[T]) split(f func([]T) ([]T, []T)) (Enumerable[T], Enumerable[T]) {
+	var once sync.Once
+	var first, second []T
+	var err error
+	evaluate := func(s *scope) {
+		once.Do(func() {
+			var values []T
+			if values, err = e.ToListErr(); err == nil {
+				first, second = f(values)
+			}
+		})
+		if err != nil {
+			s.fail(err)
+		}
+	}
+	return fromSource(func(s *scope) iterator[T] {
+			evaluate(s)
+			return New(first).iterate(s)
+		}), fromSource(func(s *scope) iterator[T] {
+			evaluate(s)
+			return New(second).iterate(s)
+		})
+}
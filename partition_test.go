@@ -0,0 +1,116 @@
+package enumerable
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5})
+	even, odd := e.Partition(func(i int) bool { return i%2 == 0 })
+
+	if result := even.ToList(); !reflect.DeepEqual(result, []int{2, 4}) {
+		t.Errorf("Expected %v, got %v", []int{2, 4}, result)
+	}
+	if result := odd.ToList(); !reflect.DeepEqual(result, []int{1, 3, 5}) {
+		t.Errorf("Expected %v, got %v", []int{1, 3, 5}, result)
+	}
+}
+
+func TestPartitionEvaluatesOnce(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2, 3, 4}).Map(func(i int) int {
+		calls++
+		return i
+	})
+	small, large := e.Partition(func(i int) bool { return i < 3 })
+	small.ToList()
+	large.ToList()
+	large.Count()
+
+	if calls != 4 {
+		t.Errorf("Expected 4 calls, got %d", calls)
+	}
+}
+
+func TestPartitionSourceError(t *testing.T) {
+	failed := errors.New("failed")
+	e := FromSeqErr(func(yield func(int, error) bool) {
+		if yield(1, nil) {
+			yield(0, failed)
+		}
+	})
+	even, odd := e.Partition(func(i int) bool { return i%2 == 0 })
+
+	if _, err := even.ToListErr(); !errors.Is(err, failed) {
+		t.Errorf("Expected %v, got %v", failed, err)
+	}
+	if _, err := odd.ToListErr(); !errors.Is(err, failed) {
+		t.Errorf("Expected %v, got %v", failed, err)
+	}
+	if _, err := even.ToListErr(); !errors.Is(err, failed) {
+		t.Errorf("Expected %v on a second evaluation, got %v", failed, err)
+	}
+}
+
+func TestPartitionIsLazy(t *testing.T) {
+	calls := 0
+	e := New([]int{1, 2}).Map(func(i int) int {
+		calls++
+		return i
+	})
+	small, _ := e.Partition(func(i int) bool { return i < 2 })
+	small = small.Map(func(i int) int { return i * 10 })
+
+	if calls != 0 {
+		t.Errorf("Expected 0 calls, got %d", calls)
+	}
+	if result := small.ToList(); !reflect.DeepEqual(result, []int{10}) {
+		t.Errorf("Expected %v, got %v", []int{10}, result)
+	}
+}
+
+func TestSplitAt(t *testing.T) {
+	e := New([]int{1, 2, 3})
+	tests := []struct {
+		n      int
+		first  []int
+		second []int
+	}{
+		{-1, []int{}, []int{1, 2, 3}},
+		{0, []int{}, []int{1, 2, 3}},
+		{2, []int{1, 2}, []int{3}},
+		{5, []int{1, 2, 3}, []int{}},
+	}
+	for _, test := range tests {
+		first, second := e.SplitAt(test.n)
+		if result := first.ToList(); !reflect.DeepEqual(result, test.first) {
+			t.Errorf("SplitAt %d: expected %v, got %v", test.n, test.first, result)
+		}
+		if result := second.ToList(); !reflect.DeepEqual(result, test.second) {
+			t.Errorf("SplitAt %d: expected %v, got %v", test.n, test.second, result)
+		}
+	}
+}
+
+func TestSplitAtAppendDoesNotOverwrite(t *testing.T) {
+	first, second := New([]int{1, 2, 3}).SplitAt(1)
+	first.Append(9).ToList()
+
+	if result := second.ToList(); !reflect.DeepEqual(result, []int{2, 3}) {
+		t.Errorf("Expected %v, got %v", []int{2, 3}, result)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	e := New([]int{1, 2, 5, 1, 2})
+	first, second := e.Span(func(i int) bool { return i < 3 })
+
+	if result := first.ToList(); !reflect.DeepEqual(result, []int{1, 2}) {
+		t.Errorf("Expected %v, got %v", []int{1, 2}, result)
+	}
+	if result := second.ToList(); !reflect.DeepEqual(result, []int{5, 1, 2}) {
+		t.Errorf("Expected %v, got %v", []int{5, 1, 2}, result)
+	}
+}
@@ -0,0 +1,168 @@
+package enumerable
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Op names an operation in the Plan of an Enumerable[T]
+// Operations without a constant are named after the method that added them, such as "TakeWhile"
+type Op string
+
+const (
+	// OpValues is the source of an Enumerable[T] holding its values in memory
+	OpValues Op = "Values"
+	// OpSource is the source of an Enumerable[T] that pulls its values lazily, such as FromSeq
+	OpSource   Op = "Source"
+	OpMap      Op = "Map"
+	OpFilter   Op = "Filter"
+	OpTake     Op = "Take"
+	OpTakeLast Op = "TakeLast"
+	OpSkip     Op = "Skip"
+	OpSkipLast Op = "SkipLast"
+	OpReverse  Op = "Reverse"
+	OpOrderBy  Op = "OrderBy"
+	OpTopK     Op = "TopK"
+)
+
+// Node is a single operation in a Plan
+// Nodes encode to JSON with encoding/json like {"op":"Take","n":5}
+type Node struct {
+	Op Op `json:"op"`
+	// N is the number of values of Values and the count of Take, Skip, TakeLast, SkipLast and TopK, zero otherwise
+	N int `json:"n,omitempty"`
+	// Fused is the number of operations the optimizer combined into this one, zero if it was not combined
+	Fused int `json:"fused,omitempty"`
+}
+
+// String formats the Node like Take(5), with *n appended when n operations were fused into it
+func (n Node) String() string {
+	s := string(n.Op)
+	switch n.Op {
+	case OpValues, OpTake, OpSkip, OpTakeLast, OpSkipLast, OpTopK:
+		s += fmt.Sprintf("(%d)", n.N)
+	}
+	if n.Fused > 1 {
+		s += fmt.Sprintf("*%d", n.Fused)
+	}
+	return s
+}
+
+// Plan is the chain of operations of an Enumerable[T], from its source to the last pending operation
+// Operations that change the type of the values, such as TransformErr, start a new Plan with a Source
+type Plan []Node
+
+// String formats the Plan like Values(10) -> Map*2 -> Take(5)
+func (p Plan) String() string {
+	nodes := make([]string, len(p))
+	for i, n := range p {
+		nodes[i] = n.String()
+	}
+	return strings.Join(nodes, " -> ")
+}
+
+// stage is an operation on the stack of an Enumerable[T]
+// The arguments are recorded for the operations the optimizer rewrites
+type stage[T any] struct {
+	op    Op
+	apply func(Enumerable[T]) Enumerable[T]
+	mapf  func(T) T
+	pred  func(T) bool
+	n     int
+	cmp   func(a, b T) int
+	fused int
+}
+
+func (s stage[T]) node() Node {
+	return Node{Op: s.op, N: s.n, Fused: s.fused}
+}
+
+func (s stage[T]) count() int {
+	return max(s.fused, 1)
+}
+
+// Plan returns the operations of the Enumerable[T] that have not been applied yet
+func (e Enumerable[T]) Plan() Plan {
+	plan := Plan{{Op: OpValues, N: len(e.values)}}
+	if e.source != nil {
+		plan[0] = Node{Op: OpSource}
+	}
+	for _, s := range e.stack {
+		plan = append(plan, s.node())
+	}
+	return plan
+}
+
+// Optimize rewrites the pending operations of the Enumerable[T] into an equivalent plan that does less work
+// Adjacent Maps and adjacent Filters are fused into one, Take and Skip are moved before Maps, Take(a).Take(b)
+// and Skip(a).Skip(b) are combined, Reverses are moved after Maps and Filters and pairs of them removed,
+// and OrderBy followed by Take is replaced by a TopK holding only the values taken
+// The optimized Enumerable[T] returns the same values, but calls to the functions of Maps and Filters
+// may happen fewer times or in a different order, so they should not have side effects
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) Optimize() Enumerable[T] {
+	e.stack = optimize(e.stack)
+	return e
+}
+
+// optimize applies the rewrites to adjacent stages until none applies
+func optimize[T any](stack []stage[T]) []stage[T] {
+	for changed := true; changed; {
+		changed = false
+		result := make([]stage[T], 0, len(stack))
+		for _, s := range stack {
+			if len(result) > 0 {
+				if replaced, ok := rewrite(result[len(result)-1], s); ok {
+					result = append(result[:len(result)-1], replaced...)
+					changed = true
+					continue
+				}
+			}
+			result = append(result, s)
+		}
+		stack = result
+	}
+	return stack
+}
+
+// rewrite returns the stages that replace a followed by b, false if there is no rewrite
+// Each rewrite removes a stage or moves Take, Skip and Reverse in a single direction so optimize terminates
+func rewrite[T any](a, b stage[T]) ([]stage[T], bool) {
+	var none Enumerable[T]
+	switch {
+	case a.op == OpMap && b.op == OpMap:
+		f, g := a.mapf, b.mapf
+		s := none.Map(func(v T) T { return g(f(v)) }).stack[0]
+		s.fused = a.count() + b.count()
+		return []stage[T]{s}, true
+	case a.op == OpFilter && b.op == OpFilter:
+		f, g := a.pred, b.pred
+		s := none.Filter(func(v T) bool { return f(v) && g(v) }).stack[0]
+		s.fused = a.count() + b.count()
+		return []stage[T]{s}, true
+	case a.op == OpTake && b.op == OpTake:
+		s := none.Take(min(a.n, b.n)).stack[0]
+		s.fused = a.count() + b.count()
+		return []stage[T]{s}, true
+	case a.op == OpSkip && b.op == OpSkip:
+		n := a.n + b.n
+		if n < 0 {
+			n = math.MaxInt
+		}
+		s := none.Skip(n).stack[0]
+		s.fused = a.count() + b.count()
+		return []stage[T]{s}, true
+	case a.op == OpMap && (b.op == OpTake || b.op == OpSkip):
+		return []stage[T]{b, a}, true
+	case a.op == OpReverse && (b.op == OpMap || b.op == OpFilter):
+		return []stage[T]{b, a}, true
+	case a.op == OpReverse && b.op == OpReverse:
+		return nil, true
+	case a.op == OpOrderBy && b.op == OpTake:
+		s := none.BottomK(b.n, a.cmp).stack[0]
+		s.fused = a.count() + b.count()
+		return []stage[T]{s}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,150 @@
+package enumerable
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	e := New([]int{1, 2, 3}).
+		Map(func(i int) int { return i * 2 }).
+		Filter(func(i int) bool { return i > 2 }).
+		TakeWhile(func(i int) bool { return i < 10 }).
+		Take(-2)
+	result := e.Plan().String()
+	expected := "Values(3) -> Map -> Filter -> TakeWhile -> TakeLast(2)"
+
+	if result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if result := FromSeq(slices.Values([]int{1})).Skip(1).Plan().String(); result != "Source -> Skip(1)" {
+		t.Errorf("Expected %v, got %v", "Source -> Skip(1)", result)
+	}
+	if result := e.Apply().Plan().String(); result != "Values(2)" {
+		t.Errorf("Expected %v, got %v", "Values(2)", result)
+	}
+}
+
+func TestPlanOps(t *testing.T) {
+	result := New([]int{1, 2, 3}).TakeLast(2).SkipLast(1).Plan()
+	expected := Plan{{Op: OpValues, N: 3}, {Op: OpTakeLast, N: 2}, {Op: OpSkipLast, N: 1}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	double := func(i int) int { return i * 2 }
+	even := func(i int) bool { return i%2 == 0 }
+	small := func(i int) bool { return i < 30 }
+	values := []int{8, 3, 12, 5, 1, 9, 14, 7, 3, 10, 6, 2, 11}
+
+	tests := []struct {
+		name      string
+		e         Enumerable[int]
+		optimized string
+	}{
+		{
+			"fuse maps",
+			New(values).Map(double).Map(func(i int) int { return i + 1 }).Map(double),
+			"Values(13) -> Map*3",
+		},
+		{
+			"fuse filters",
+			New(values).Filter(even).Filter(small),
+			"Values(13) -> Filter*2",
+		},
+		{
+			"combine takes and skips",
+			New(values).Skip(2).Skip(3).Take(6).Take(4),
+			"Values(13) -> Skip(5)*2 -> Take(4)*2",
+		},
+		{
+			"push take and skip before maps",
+			New(values).Map(double).Skip(2).Map(double).Take(3),
+			"Values(13) -> Skip(2) -> Take(3) -> Map*2",
+		},
+		{
+			"remove double reverse",
+			New(values).Reverse().Reverse(),
+			"Values(13)",
+		},
+		{
+			"remove reverse around maps and filters",
+			New(values).Reverse().Map(double).Filter(small).Reverse().Take(3),
+			"Values(13) -> Map -> Filter -> Take(3)",
+		},
+		{
+			"move single reverse after filter",
+			New(values).Reverse().Filter(even),
+			"Values(13) -> Filter -> Reverse",
+		},
+		{
+			"collapse sort and take",
+			New(values).OrderBy(cmp.Compare[int]).Map(double).Take(4),
+			"Values(13) -> TopK(4)*2 -> Map",
+		},
+		{
+			"keep opaque operations in place",
+			New(values).Map(double).TakeWhile(small).Map(double).Take(2),
+			"Values(13) -> Map -> TakeWhile -> Take(2) -> Map",
+		},
+		{
+			"keep take before filter",
+			New(values).Take(5).Filter(even).Take(-1),
+			"Values(13) -> Take(5) -> Filter -> TakeLast(1)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			optimized := test.e.Optimize()
+			if result := optimized.Plan().String(); result != test.optimized {
+				t.Errorf("Expected %v, got %v", test.optimized, result)
+			}
+			if result, expected := optimized.ToList(), test.e.ToList(); !reflect.DeepEqual(result, expected) {
+				t.Errorf("Expected %v, got %v", expected, result)
+			}
+		})
+	}
+}
+
+func TestOptimizeSortTakeStable(t *testing.T) {
+	e := New([]string{"bb", "a", "cc", "ddd", "ee", "f"}).
+		OrderBy(func(a, b string) int { return cmp.Compare(len(a), len(b)) }).
+		Take(4)
+	result := e.Optimize().ToList()
+	expected := e.ToList()
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if !reflect.DeepEqual(result, []string{"a", "f", "bb", "cc"}) {
+		t.Errorf("Expected %v, got %v", []string{"a", "f", "bb", "cc"}, result)
+	}
+}
+
+func TestOptimizeDoesNotChangeOriginal(t *testing.T) {
+	e := New([]int{1, 2, 3}).Map(func(i int) int { return i + 1 }).Map(func(i int) int { return i * 3 })
+	e.Optimize()
+
+	if result := e.Plan().String(); result != "Values(3) -> Map -> Map" {
+		t.Errorf("Expected %v, got %v", "Values(3) -> Map -> Map", result)
+	}
+}
+
+func TestStagesNotShared(t *testing.T) {
+	identity := func(i int) int { return i }
+	base := New([]int{1, 2, 3}).Map(identity).Map(identity).Map(identity)
+	a := base.Map(func(i int) int { return i * 10 })
+	b := base.Map(func(i int) int { return i * 100 })
+
+	if result := a.ToList(); !reflect.DeepEqual(result, []int{10, 20, 30}) {
+		t.Errorf("Expected %v, got %v", []int{10, 20, 30}, result)
+	}
+	if result := b.ToList(); !reflect.DeepEqual(result, []int{100, 200, 300}) {
+		t.Errorf("Expected %v, got %v", []int{100, 200, 300}, result)
+	}
+}
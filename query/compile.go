@@ -0,0 +1,288 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sdehm/go-enumerable/expr"
+	"github.com/sdehm/go-enumerable/internal/dbfield"
+)
+
+// ErrInvalid is returned when a query cannot be compiled to SQL
+var ErrInvalid = errors.New("query: invalid query")
+
+// SQL compiles the query to a statement for dialect d and the arguments for its placeholders
+func (q Query[T]) SQL(d Dialect) (string, []any, error) {
+	columns, err := columnsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return "", nil, err
+	}
+	c := &compiler{dialect: d, row: reflect.TypeFor[T](), columns: columns}
+	if err := writeQuery(c, q); err != nil {
+		return "", nil, err
+	}
+	return c.sql.String(), c.args, nil
+}
+
+// columnsOf returns the columns of the struct type t
+func columnsOf(t reflect.Type) ([]dbfield.Field, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %v is not a struct", ErrInvalid, t)
+	}
+	columns := dbfield.Fields(t)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: %v has no columns", ErrInvalid, t)
+	}
+	return columns, nil
+}
+
+type compiler struct {
+	dialect Dialect
+	row     reflect.Type
+	columns []dbfield.Field
+	sql     strings.Builder
+	args    []any
+	depth   int
+}
+
+func writeQuery[T any](c *compiler, q Query[T]) error {
+	c.write("SELECT ")
+	for i, col := range c.columns {
+		if i > 0 {
+			c.write(", ")
+		}
+		c.write(c.dialect.Quote(col.Column))
+	}
+	c.write(" FROM ")
+	if q.inner != nil {
+		c.depth++
+		alias := "t" + strconv.Itoa(c.depth)
+		c.write("(")
+		if err := writeQuery(c, *q.inner); err != nil {
+			return err
+		}
+		c.write(") AS " + c.dialect.Quote(alias))
+	} else {
+		c.write(c.dialect.Quote(q.table))
+	}
+	for _, f := range q.where {
+		if _, err := expr.Check(f, reflect.TypeFor[T]()); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalid, err)
+		}
+	}
+	if len(q.where) > 0 {
+		c.write(" WHERE ")
+		if err := c.condition(expr.Expr{Op: expr.OpAnd, Args: q.where}, precedence(expr.OpOr)); err != nil {
+			return err
+		}
+	}
+	for i, o := range q.order {
+		if i == 0 {
+			c.write(" ORDER BY ")
+		} else {
+			c.write(", ")
+		}
+		if err := c.value(o.key); err != nil {
+			return err
+		}
+		if o.desc {
+			c.write(" DESC")
+		}
+	}
+	if limit := c.dialect.Limit(q.limit, q.offset); limit != "" {
+		c.write(" " + limit)
+	}
+	return nil
+}
+
+// precedence orders the boolean operators, operands with a lower precedence than their parent are parenthesized
+func precedence(op expr.Op) int {
+	switch op {
+	case expr.OpOr:
+		return 1
+	case expr.OpAnd:
+		return 2
+	case expr.OpNot:
+		return 3
+	default:
+		return 4
+	}
+}
+
+var comparisons = map[expr.Op]string{
+	expr.OpEq: "=",
+	expr.OpNe: "<>",
+	expr.OpLt: "<",
+	expr.OpLe: "<=",
+	expr.OpGt: ">",
+	expr.OpGe: ">=",
+}
+
+// condition writes the boolean expression e, parenthesized if it binds less tightly than parent
+func (c *compiler) condition(e expr.Expr, parent int) error {
+	p := precedence(e.Op)
+	if p < parent {
+		c.write("(")
+		defer c.write(")")
+	}
+	switch e.Op {
+	case expr.OpAnd, expr.OpOr:
+		if len(e.Args) == 1 {
+			return c.condition(e.Args[0], parent)
+		}
+		for i, a := range e.Args {
+			if i > 0 {
+				c.write(" " + strings.ToUpper(string(e.Op)) + " ")
+			}
+			if err := c.condition(a, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	case expr.OpNot:
+		if err := c.arity(e, 1); err != nil {
+			return err
+		}
+		// the operand is always parenthesized for readability since NOT binds less tightly than comparisons
+		c.write("NOT ")
+		return c.condition(e.Args[0], precedence(expr.OpField)+1)
+	case expr.OpEq, expr.OpNe, expr.OpLt, expr.OpLe, expr.OpGt, expr.OpGe:
+		if err := c.arity(e, 2); err != nil {
+			return err
+		}
+		return c.comparison(e)
+	case expr.OpIn:
+		if len(e.Args) == 0 {
+			return c.arity(e, 1)
+		}
+		if len(e.Args) == 1 {
+			// IN with an empty list is not valid SQL and matches nothing
+			c.write("1 = 0")
+			return nil
+		}
+		value, err := c.operand(e.Args[0], c.isString(e.Args[0]))
+		if err != nil {
+			return err
+		}
+		c.write(value + " IN (")
+		for i, a := range e.Args[1:] {
+			if i > 0 {
+				c.write(", ")
+			}
+			if err := c.value(a); err != nil {
+				return err
+			}
+		}
+		c.write(")")
+		return nil
+	case expr.OpPrefix, expr.OpSuffix, expr.OpContains:
+		if err := c.arity(e, 2); err != nil {
+			return err
+		}
+		return c.match(e)
+	default:
+		return fmt.Errorf("%w: %s is not a boolean expression", ErrInvalid, e.Op)
+	}
+}
+
+func (c *compiler) comparison(e expr.Expr) error {
+	left, right := e.Args[0], e.Args[1]
+	if isNull(left) && (e.Op == expr.OpEq || e.Op == expr.OpNe) {
+		left, right = right, left
+	}
+	if isNull(right) && (e.Op == expr.OpEq || e.Op == expr.OpNe) {
+		if err := c.value(left); err != nil {
+			return err
+		}
+		if e.Op == expr.OpEq {
+			c.write(" IS NULL")
+		} else {
+			c.write(" IS NOT NULL")
+		}
+		return nil
+	}
+	// a binary left operand makes the comparison byte by byte in every dialect
+	value, err := c.operand(left, c.isString(left) || c.isString(right))
+	if err != nil {
+		return err
+	}
+	c.write(value + " " + comparisons[e.Op] + " ")
+	return c.value(right)
+}
+
+// isString returns true if e is a string field or constant
+func (c *compiler) isString(e expr.Expr) bool {
+	t, err := expr.Check(e, c.row)
+	return err == nil && t != nil && t.Kind() == reflect.String
+}
+
+// isNull returns true if e is the nil constant
+func isNull(e expr.Expr) bool {
+	return e.Op == expr.OpConst && e.Value == nil
+}
+
+// match writes the case-sensitive pattern match of HasPrefix, HasSuffix and Contains
+func (c *compiler) match(e expr.Expr) error {
+	s, ok := e.Args[1].Value.(string)
+	if e.Args[1].Op != expr.OpConst || !ok {
+		return fmt.Errorf("%w: %s needs a string constant", ErrInvalid, e.Op)
+	}
+	value, err := c.operand(e.Args[0], false)
+	if err != nil {
+		return err
+	}
+	c.write(c.dialect.Match(value, s, e.Op == expr.OpPrefix, e.Op == expr.OpSuffix, c.placeholder))
+	return nil
+}
+
+// value writes the field or constant e
+func (c *compiler) value(e expr.Expr) error {
+	value, err := c.operand(e, false)
+	if err != nil {
+		return err
+	}
+	c.write(value)
+	return nil
+}
+
+// operand returns the field or constant e, compared byte by byte if binary, adding the argument of a constant
+func (c *compiler) operand(e expr.Expr, binary bool) (string, error) {
+	var value string
+	switch e.Op {
+	case expr.OpField:
+		i := slices.IndexFunc(c.columns, func(col dbfield.Field) bool { return col.Name == e.Name })
+		if i < 0 {
+			return "", fmt.Errorf("%w: unknown field %q", ErrInvalid, e.Name)
+		}
+		value = c.dialect.Quote(c.columns[i].Column)
+	case expr.OpConst:
+		value = c.placeholder(e.Value)
+	default:
+		return "", fmt.Errorf("%w: %s is not a value", ErrInvalid, e.Op)
+	}
+	if binary {
+		value = c.dialect.Binary(value)
+	}
+	return value, nil
+}
+
+func (c *compiler) arity(e expr.Expr, n int) error {
+	if len(e.Args) != n {
+		return fmt.Errorf("%w: %s needs %d arguments, got %d", ErrInvalid, e.Op, n, len(e.Args))
+	}
+	return nil
+}
+
+// placeholder adds the argument v and returns its placeholder
+func (c *compiler) placeholder(v any) string {
+	c.args = append(c.args, v)
+	return c.dialect.Placeholder(len(c.args))
+}
+
+func (c *compiler) write(s string) {
+	c.sql.WriteString(s)
+}
@@ -0,0 +1,151 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect renders the parts of a statement that differ between databases
+type Dialect interface {
+	// Quote quotes an identifier
+	Quote(ident string) string
+	// Placeholder returns the placeholder for the nth parameter, starting at 1
+	Placeholder(n int) string
+	// Limit returns the clause limiting the rows returned, limit is negative when there is no limit
+	Limit(limit, offset int) string
+	// Binary returns the string operand value so that = and IN compare it byte by byte, whatever the collation
+	Binary(value string) string
+	// Match returns the case-sensitive condition that value contains s, at its start if start and at its end if end
+	// param adds an argument and returns its placeholder
+	Match(value, s string, start, end bool, param func(any) string) string
+}
+
+var (
+	// SQLite quotes identifiers with double quotes and uses ? placeholders
+	// Strings are compared with the BINARY collation and matched with GLOB since LIKE ignores ASCII case
+	SQLite Dialect = sqlite{}
+	// Postgres quotes identifiers with double quotes and uses numbered $n placeholders
+	Postgres Dialect = postgres{}
+	// MySQL quotes identifiers with backticks and uses ? placeholders
+	// Strings are cast to BINARY before they are compared or matched since the default collations ignore case
+	MySQL Dialect = mysql{}
+)
+
+type sqlite struct{}
+
+func (sqlite) Quote(ident string) string {
+	return quote(ident, `"`)
+}
+
+func (sqlite) Placeholder(int) string {
+	return "?"
+}
+
+func (sqlite) Limit(limit, offset int) string {
+	// SQLite only accepts OFFSET after LIMIT, a negative limit means no limit
+	return limitOffset(limit, offset, "-1")
+}
+
+func (sqlite) Binary(value string) string {
+	return value + " COLLATE BINARY"
+}
+
+func (sqlite) Match(value, s string, start, end bool, param func(any) string) string {
+	// GLOB has no escape character, its wildcards are matched literally inside brackets
+	pattern := globEscaper.Replace(s)
+	if !start {
+		pattern = "*" + pattern
+	}
+	if !end {
+		pattern += "*"
+	}
+	return value + " GLOB " + param(pattern)
+}
+
+var globEscaper = strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]")
+
+type postgres struct{}
+
+func (postgres) Quote(ident string) string {
+	return quote(ident, `"`)
+}
+
+func (postgres) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (postgres) Limit(limit, offset int) string {
+	return limitOffset(limit, offset, "")
+}
+
+func (postgres) Binary(value string) string {
+	// the default collations are deterministic, strings are only equal when their bytes are
+	return value
+}
+
+func (postgres) Match(value, s string, start, end bool, param func(any) string) string {
+	return value + " LIKE " + param(likePattern(s, start, end)) + " ESCAPE '!'"
+}
+
+type mysql struct{}
+
+func (mysql) Quote(ident string) string {
+	return quote(ident, "`")
+}
+
+func (mysql) Placeholder(int) string {
+	return "?"
+}
+
+func (mysql) Limit(limit, offset int) string {
+	// MySQL only accepts OFFSET after LIMIT and documents the largest unsigned integer as the way to skip without a limit
+	return limitOffset(limit, offset, "18446744073709551615")
+}
+
+func (mysql) Binary(value string) string {
+	return "CAST(" + value + " AS BINARY)"
+}
+
+func (mysql) Match(value, s string, start, end bool, param func(any) string) string {
+	return value + " LIKE CAST(" + param(likePattern(s, start, end)) + " AS BINARY) ESCAPE '!'"
+}
+
+// quote quotes each dot separated part of ident with q, doubling any q inside it
+func quote(ident, q string) string {
+	parts := strings.Split(ident, ".")
+	for i, p := range parts {
+		parts[i] = q + strings.ReplaceAll(p, q, q+q) + q
+	}
+	return strings.Join(parts, ".")
+}
+
+// likeEscaper escapes the LIKE wildcards with !, which unlike backslash needs no escaping in any dialect's string literals
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// likePattern returns the LIKE pattern escaped with ! matching s, at the start of the value if start
+// and at its end if end
+func likePattern(s string, start, end bool) string {
+	pattern := likeEscaper.Replace(s)
+	if !start {
+		pattern = "%" + pattern
+	}
+	if !end {
+		pattern += "%"
+	}
+	return pattern
+}
+
+// limitOffset renders LIMIT and OFFSET clauses, unlimited is the limit used when there is only an offset
+// or empty if OFFSET can be used alone
+func limitOffset(limit, offset int, unlimited string) string {
+	var clauses []string
+	if limit >= 0 {
+		clauses = append(clauses, "LIMIT "+strconv.Itoa(limit))
+	} else if offset > 0 && unlimited != "" {
+		clauses = append(clauses, "LIMIT "+unlimited)
+	}
+	if offset > 0 {
+		clauses = append(clauses, "OFFSET "+strconv.Itoa(offset))
+	}
+	return strings.Join(clauses, " ")
+}
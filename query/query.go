@@ -0,0 +1,105 @@
+// Package query builds SQL statements from pipelines of expr expression trees
+//
+// A Query mirrors the Enumerable operations that have a SQL equivalent, stages keep their pipeline semantics
+// so a Filter after a Take filters the taken rows rather than the table:
+//
+//	q := query.From[User]("users").
+//		Filter(expr.Field("Age").Gt(30).And(expr.Field("Name").HasPrefix("A"))).
+//		OrderBy(expr.Field("Name")).
+//		Take(10)
+//	sql, args, err := q.SQL(query.Postgres)
+//
+// Fields are named by their Go struct field and mapped to columns by the db struct tag,
+// or the lower case field name when there is no tag. Fields tagged db:"-" are ignored.
+// The same mapping is used by enumerable.FromRows to read the rows of the statement back into T
+//
+// Strings are compared and matched case-sensitively in every dialect, like expr evaluates them in Go,
+// whatever the collation of the column. ORDER BY and, on Postgres, <, <=, > and >= on strings follow the collation
+package query
+
+import (
+	"slices"
+
+	"github.com/sdehm/go-enumerable/expr"
+)
+
+// Query is a SELECT statement over the rows of a table scanned into T
+type Query[T any] struct {
+	table string
+	// inner is the query selected from when stages follow a Take or Skip
+	inner  *Query[T]
+	where  []expr.Expr
+	order  []order
+	limit  int
+	offset int
+}
+
+type order struct {
+	key  expr.Expr
+	desc bool
+}
+
+// From returns a Query selecting every row of table
+func From[T any](table string) Query[T] {
+	return Query[T]{table: table, limit: -1}
+}
+
+// Filter keeps the rows for which the boolean expression f is true
+func (q Query[T]) Filter(f expr.Expr) Query[T] {
+	q = q.wrapLimited()
+	q.where = append(slices.Clip(q.where), f)
+	return q
+}
+
+// OrderBy sorts the rows by key, an earlier OrderBy breaks ties between equal keys
+// SQL sorts are not stable, so rows with equal keys are otherwise returned in any order
+func (q Query[T]) OrderBy(key expr.Expr) Query[T] {
+	return q.orderBy(key, false)
+}
+
+// OrderByDescending sorts the rows by key in descending order, an earlier OrderBy breaks ties between equal keys
+// SQL sorts are not stable, so rows with equal keys are otherwise returned in any order
+func (q Query[T]) OrderByDescending(key expr.Expr) Query[T] {
+	return q.orderBy(key, true)
+}
+
+func (q Query[T]) orderBy(key expr.Expr, desc bool) Query[T] {
+	q = q.wrapLimited()
+	// sorting by key after an earlier sort makes key the first sort key and the earlier keys its tiebreakers
+	q.order = append([]order{{key, desc}}, q.order...)
+	return q
+}
+
+// Take keeps the first n rows
+// Panics if n is negative
+func (q Query[T]) Take(n int) Query[T] {
+	if n < 0 {
+		panic("query: take count must not be negative")
+	}
+	if q.limit < 0 || n < q.limit {
+		q.limit = n
+	}
+	return q
+}
+
+// Skip skips the first n rows
+// Panics if n is negative
+func (q Query[T]) Skip(n int) Query[T] {
+	if n < 0 {
+		panic("query: skip count must not be negative")
+	}
+	q.offset += n
+	if q.limit >= 0 {
+		q.limit = max(q.limit-n, 0)
+	}
+	return q
+}
+
+// wrapLimited returns a query selecting from q when q has a limit or offset so that later stages apply to its rows
+// The order of q is kept since a subquery's order is not preserved by the query selecting from it
+func (q Query[T]) wrapLimited() Query[T] {
+	if q.limit < 0 && q.offset == 0 {
+		return q
+	}
+	return Query[T]{table: q.table, inner: &q, order: q.order, limit: -1}
+}
@@ -0,0 +1,140 @@
+package query
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sdehm/go-enumerable/expr"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type User struct {
+	ID        int    `db:"id"`
+	Name      string `db:"name"`
+	Age       int
+	CreatedAt string `db:"created_at"`
+	Password  string `db:"-"`
+	notes     string
+}
+
+var dialects = []struct {
+	name    string
+	dialect Dialect
+}{
+	{"sqlite", SQLite},
+	{"postgres", Postgres},
+	{"mysql", MySQL},
+}
+
+func TestSQLGolden(t *testing.T) {
+	users := From[User]("users")
+	tests := []struct {
+		name  string
+		query Query[User]
+	}{
+		{"select", users},
+		{"filter", users.Filter(expr.Field("Age").Gt(30).And(expr.Field("Name").HasPrefix("A")))},
+		{"filter_multiple", users.Filter(expr.Field("Age").Ge(18)).Filter(expr.Field("Name").Eq("Bob").Or(expr.Field("Name").Eq("Carol")))},
+		{"filter_not", users.Filter(expr.Field("Age").Lt(18).Or(expr.Field("Age").Gt(65)).Not())},
+		{"filter_null", users.Filter(expr.Field("CreatedAt").Eq(nil).And(expr.Field("Name").Ne(nil)))},
+		{"filter_null_left", users.Filter(expr.Const(nil).Ne(expr.Field("Name")))},
+		{"filter_in", users.Filter(expr.Field("ID").In(1, 2, 3))},
+		{"filter_in_empty", users.Filter(expr.Field("ID").In())},
+		{"filter_like_escaped", users.Filter(expr.Field("Name").Contains("50%_off!").Or(expr.Field("Name").HasSuffix("son")))},
+		{"filter_case_sensitive", users.Filter(expr.Field("Name").Eq("Bob").Or(expr.Field("Name").In("Al", "Cy")).Or(expr.Field("Name").HasPrefix("[a*?]")))},
+		{"filter_fields", users.Filter(expr.Field("ID").Le(expr.Field("Age")))},
+		{"order", users.OrderBy(expr.Field("Name")).OrderByDescending(expr.Field("Age"))},
+		{"take", users.Filter(expr.Field("Age").Gt(30)).OrderBy(expr.Field("Name")).Take(10)},
+		{"skip", users.Skip(20)},
+		{"skip_take", users.OrderBy(expr.Field("ID")).Skip(20).Take(10)},
+		{"take_skip", users.OrderBy(expr.Field("ID")).Take(10).Skip(3)},
+		{"take_filter", users.OrderBy(expr.Field("Age")).Take(10).Filter(expr.Field("Name").HasPrefix("A"))},
+		{"take_order_take", users.Take(10).OrderBy(expr.Field("Name")).Skip(1).Take(5).Filter(expr.Field("Age").Ne(0))},
+		{"schema_table", From[User]("app.users").Take(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			for _, d := range dialects {
+				sql, args, err := tt.query.SQL(d.dialect)
+				if err != nil {
+					t.Fatalf("Unexpected error for %s: %v", d.name, err)
+				}
+				fmt.Fprintf(&b, "-- %s --\n%s\n", d.name, sql)
+				for i, a := range args {
+					fmt.Fprintf(&b, "%d: %#v\n", i+1, a)
+				}
+			}
+			golden(t, filepath.Join("testdata", tt.name+".golden"), b.String())
+		})
+	}
+}
+
+func golden(t *testing.T, path, actual string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != string(expected) {
+		t.Errorf("Expected %s to be\n%s\ngot\n%s", path, expected, actual)
+	}
+}
+
+func TestSQLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  func(Dialect) (string, []any, error)
+	}{
+		{"unknown field", From[User]("users").Filter(expr.Field("Email").Eq("a")).SQL},
+		{"ignored field", From[User]("users").Filter(expr.Field("Password").Eq("a")).SQL},
+		{"unexported field", From[User]("users").OrderBy(expr.Field("notes")).SQL},
+		{"type error", From[User]("users").Filter(expr.Field("Name").Gt(30)).SQL},
+		{"not boolean", From[User]("users").Filter(expr.Field("Age")).SQL},
+		{"pattern not constant", From[User]("users").Filter(expr.Expr{Op: expr.OpPrefix, Args: []expr.Expr{expr.Field("Name"), expr.Field("Name")}}).SQL},
+		{"pattern not string", From[User]("users").Filter(expr.Expr{Op: expr.OpPrefix, Args: []expr.Expr{expr.Field("Name"), expr.Const(1)}}).SQL},
+		{"arity", From[User]("users").Filter(expr.Expr{Op: expr.OpEq, Args: []expr.Expr{expr.Field("Name")}}).SQL},
+		{"not struct", From[int]("numbers").SQL},
+		{"no columns", From[struct{ a int }]("empty").SQL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.sql(SQLite)
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Expected %v, got %v", ErrInvalid, err)
+			}
+		})
+	}
+}
+
+func TestQueryImmutable(t *testing.T) {
+	base := From[User]("users").Filter(expr.Field("Age").Gt(1))
+	a := base.Filter(expr.Field("Name").Eq("a"))
+	b := base.Filter(expr.Field("Name").Eq("b"))
+	_, argsA, _ := a.SQL(SQLite)
+	_, argsB, _ := b.SQL(SQLite)
+
+	if argsA[1] != "a" || argsB[1] != "b" {
+		t.Errorf("Expected branches to keep their own filters, got %v and %v", argsA, argsB)
+	}
+}
+
+func TestTakeNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic")
+		}
+	}()
+	From[User]("users").Take(-1)
+}
@@ -0,0 +1,156 @@
+package enumerable
+
+import (
+	"container/heap"
+	"math"
+	"math/rand/v2"
+)
+
+// Shuffle returns the values of the Enumerable[T] in a random order using a Fisher-Yates shuffle
+// Uses a randomly seeded source if src is nil
+// A seed is drawn from src once, so every evaluation of the result gives the same order and results are
+// reproducible for a seeded src
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) Shuffle(src rand.Source) Enumerable[T] {
+	newRand := seedRand(src)
+	return e.lazy("Shuffle", func(e Enumerable[T]) Enumerable[T] {
+		rng := newRand()
+		values := make([]T, len(e.values))
+		copy(values, e.values)
+		rng.Shuffle(len(values), func(i, j int) {
+			values[i], values[j] = values[j], values[i]
+		})
+		return New(values)
+	})
+}
+
+// Sample returns n values of the Enumerable[T] chosen at random without replacement, in random order
+// If n is greater than the length of the Enumerable[T], returns all values shuffled
+// Uses a randomly seeded source if src is nil, every evaluation chooses the same values like Shuffle
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) Sample(n int, src rand.Source) Enumerable[T] {
+	newRand := seedRand(src)
+	return e.lazy("Sample", func(e Enumerable[T]) Enumerable[T] {
+		rng := newRand()
+		values := make([]T, len(e.values))
+		copy(values, e.values)
+		size := min(max(n, 0), len(values))
+		// partial Fisher-Yates shuffle that stops after the first size positions
+		for i := 0; i < size; i++ {
+			j := i + rng.IntN(len(values)-i)
+			values[i], values[j] = values[j], values[i]
+		}
+		return New(values[:size])
+	})
+}
+
+// ReservoirSample returns n values of the Enumerable[T] chosen uniformly at random in a single pass
+// Holds at most n values in memory, so the length of the Enumerable[T] does not need to be known
+// Values are returned in the order they appeared
+// Uses a randomly seeded source if src is nil, every evaluation of the same values chooses the same values like Shuffle
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) ReservoirSample(n int, src rand.Source) Enumerable[T] {
+	newRand := seedRand(src)
+	return e.stream("ReservoirSample", func(next iterator[T]) iterator[T] {
+		return deferred(func() []T {
+			return reservoirSample(next, n, newRand())
+		})
+	})
+}
+
+// WeightedSample returns n values of the Enumerable[T] chosen at random without replacement in a single pass
+// Each value is chosen with probability proportional to its weight, values with a weight of zero or less are never chosen
+// Values are returned in the order they appeared
+// Uses a randomly seeded source if src is nil, every evaluation of the same values chooses the same values like Shuffle
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) WeightedSample(n int, weight func(T) float64, src rand.Source) Enumerable[T] {
+	newRand := seedRand(src)
+	return e.stream("WeightedSample", func(next iterator[T]) iterator[T] {
+		return deferred(func() []T {
+			return weightedSample(next, n, weight, newRand())
+		})
+	})
+}
+
+func reservoirSample[T any](next iterator[T], n int, rng *rand.Rand) []T {
+	if n <= 0 {
+		return nil
+	}
+	reservoir := make([]indexed[T], 0, n)
+	i := 0
+	for v, ok := next(); ok; v, ok = next() {
+		if i < n {
+			reservoir = append(reservoir, indexed[T]{i, v})
+		} else if j := rng.IntN(i + 1); j < n {
+			reservoir[j] = indexed[T]{i, v}
+		}
+		i++
+	}
+	sortIndexed(reservoir)
+	return unwrapIndexed(reservoir)
+}
+
+// weightedSample keeps the n values with the largest keys u^(1/w) as described by Efraimidis and Spirakis
+func weightedSample[T any](next iterator[T], n int, weight func(T) float64, rng *rand.Rand) []T {
+	if n <= 0 {
+		return nil
+	}
+	h := &keyedHeap[T]{}
+	i := 0
+	for v, ok := next(); ok; v, ok = next() {
+		w := weight(v)
+		i++
+		if w <= 0 {
+			continue
+		}
+		// compare logarithms of the keys to avoid underflow with small weights
+		key := math.Log(1-rng.Float64()) / w
+		if h.Len() < n {
+			heap.Push(h, keyed[T]{key, indexed[T]{i - 1, v}})
+		} else if key > h.items[0].key {
+			h.items[0] = keyed[T]{key, indexed[T]{i - 1, v}}
+			heap.Fix(h, 0)
+		}
+	}
+	result := make([]indexed[T], len(h.items))
+	for j, item := range h.items {
+		result[j] = item.value
+	}
+	sortIndexed(result)
+	return unwrapIndexed(result)
+}
+
+type keyed[T any] struct {
+	key   float64
+	value indexed[T]
+}
+
+// keyedHeap is a min heap of keyed values
+type keyedHeap[T any] struct {
+	items []keyed[T]
+}
+
+func (h *keyedHeap[T]) Len() int           { return len(h.items) }
+func (h *keyedHeap[T]) Less(i, j int) bool { return h.items[i].key < h.items[j].key }
+func (h *keyedHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *keyedHeap[T]) Push(x any)         { h.items = append(h.items, x.(keyed[T])) }
+
+func (h *keyedHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// seedRand draws a seed from src, or a random seed if src is nil, and returns a function creating generators from it
+// Each evaluation creates its own generator so concurrent evaluations do not share state
+func seedRand(src rand.Source) func() *rand.Rand {
+	var seed1, seed2 uint64
+	if src == nil {
+		seed1, seed2 = rand.Uint64(), rand.Uint64()
+	} else {
+		seed1, seed2 = src.Uint64(), src.Uint64()
+	}
+	return func() *rand.Rand {
+		return rand.New(rand.NewPCG(seed1, seed2))
+	}
+}
@@ -0,0 +1,148 @@
+package enumerable
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func seeded() rand.Source {
+	return rand.NewPCG(1, 2)
+}
+
+func TestShuffle(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	result := New(values).Shuffle(seeded()).ToList()
+	sorted := append([]int{}, result...)
+	sort.Ints(sorted)
+
+	if !reflect.DeepEqual(sorted, values) {
+		t.Errorf("Expected a permutation of %v, got %v", values, result)
+	}
+	if reflect.DeepEqual(result, values) {
+		t.Errorf("Expected values to be shuffled, got %v", result)
+	}
+	if values[0] != 1 || values[7] != 8 {
+		t.Errorf("Expected source to be unchanged, got %v", values)
+	}
+}
+
+func TestShuffleReproducible(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5, 6, 7, 8})
+	first := e.Shuffle(seeded()).ToList()
+	second := e.Shuffle(seeded()).ToList()
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected %v, got %v", first, second)
+	}
+}
+
+func TestShuffleEvaluations(t *testing.T) {
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+	shuffled := []Enumerable[int]{
+		New(values).Shuffle(nil),
+		New(values).Sample(10, nil),
+		New(values).ReservoirSample(10, nil),
+		New(values).WeightedSample(10, func(v int) float64 { return float64(v) }, nil),
+	}
+	for _, e := range shuffled {
+		expected := e.ToList()
+		results := make([][]int, 8)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = e.ToList()
+			}()
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("Expected every evaluation to give %v, got %v", expected, result)
+			}
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5, 6, 7, 8})
+	result := e.Sample(3, seeded()).ToList()
+	seen := map[int]bool{}
+	for _, v := range result {
+		seen[v] = true
+	}
+
+	if len(result) != 3 || len(seen) != 3 {
+		t.Errorf("Expected 3 distinct values, got %v", result)
+	}
+	if result := e.Sample(10, seeded()).Count(); result != 8 {
+		t.Errorf("Expected %d, got %d", 8, result)
+	}
+	if result := e.Sample(-1, seeded()).Count(); result != 0 {
+		t.Errorf("Expected %d, got %d", 0, result)
+	}
+}
+
+func TestReservoirSample(t *testing.T) {
+	e := New([]int{1, 2, 3, 4, 5, 6, 7, 8})
+	result := e.ReservoirSample(3, seeded()).ToList()
+
+	if len(result) != 3 {
+		t.Errorf("Expected 3 values, got %v", result)
+	}
+	if !sort.IntsAreSorted(result) {
+		t.Errorf("Expected values in their original order, got %v", result)
+	}
+	if result := e.ReservoirSample(10, seeded()).ToList(); !reflect.DeepEqual(result, e.ToList()) {
+		t.Errorf("Expected %v, got %v", e.ToList(), result)
+	}
+}
+
+func TestReservoirSampleUniform(t *testing.T) {
+	src := seeded()
+	counts := make([]int, 10)
+	e := New([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	for i := 0; i < 10000; i++ {
+		e.ReservoirSample(2, src).ForEach(func(v int) { counts[v]++ })
+	}
+	// each value is expected 2000 times
+	for v, c := range counts {
+		if c < 1800 || c > 2200 {
+			t.Errorf("Expected value %d about 2000 times, got %d", v, c)
+		}
+	}
+}
+
+func TestWeightedSample(t *testing.T) {
+	src := seeded()
+	e := New([]int{0, 1, 2, 3})
+	weight := func(v int) float64 { return float64(v * v) }
+	counts := make([]int, 4)
+	for i := 0; i < 1000; i++ {
+		e.WeightedSample(1, weight, src).ForEach(func(v int) { counts[v]++ })
+	}
+
+	if counts[0] != 0 {
+		t.Errorf("Expected zero weight to never be chosen, got %d", counts[0])
+	}
+	// weights 1, 4 and 9 give expected counts of about 71, 286 and 643
+	if !(counts[1] < counts[2] && counts[2] < counts[3]) {
+		t.Errorf("Expected counts to follow weights, got %v", counts)
+	}
+}
+
+func TestWeightedSampleAll(t *testing.T) {
+	e := New([]int{3, 1, 2})
+	result := e.WeightedSample(5, func(int) float64 { return 1 }, seeded()).ToList()
+
+	if !reflect.DeepEqual(result, []int{3, 1, 2}) {
+		t.Errorf("Expected %v, got %v", []int{3, 1, 2}, result)
+	}
+}
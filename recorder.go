@@ -0,0 +1,84 @@
+package enumerable
+
+import (
+	"slices"
+	"sync"
+)
+
+// EventKind is the Observer method that received an Event
+type EventKind string
+
+const (
+	EventStageStart   EventKind = "StageStart"
+	EventElement      EventKind = "Element"
+	EventStageFinish  EventKind = "StageFinish"
+	EventError        EventKind = "Error"
+	EventWorkerStart  EventKind = "WorkerStart"
+	EventWorkerFinish EventKind = "WorkerFinish"
+)
+
+// Event is a call to an Observer kept by a Recorder, the fields its kind does not use are zero
+type Event struct {
+	Kind EventKind
+	// Stage is the operation of the stage and element events
+	Stage StageInfo
+	// Worker is the worker of the worker events
+	Worker WorkerInfo
+	// Index and Value are the index and the value of an Element
+	Index int
+	Value any
+	// Count is the number of values produced by a finished stage or processed by a finished worker
+	Count int
+	Err   error
+}
+
+// Recorder is an Observer keeping the events it receives in memory, for tests and debugging
+// It is safe for concurrent use
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// Events returns the events received so far in the order they were received
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+// Reset removes the events received so far
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+func (r *Recorder) record(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *Recorder) StageStart(stage StageInfo) {
+	r.record(Event{Kind: EventStageStart, Stage: stage})
+}
+
+func (r *Recorder) Element(stage StageInfo, index int, value any) {
+	r.record(Event{Kind: EventElement, Stage: stage, Index: index, Value: value})
+}
+
+func (r *Recorder) StageFinish(stage StageInfo, out int) {
+	r.record(Event{Kind: EventStageFinish, Stage: stage, Count: out})
+}
+
+func (r *Recorder) Error(stage StageInfo, err error) {
+	r.record(Event{Kind: EventError, Stage: stage, Err: err})
+}
+
+func (r *Recorder) WorkerStart(worker WorkerInfo) {
+	r.record(Event{Kind: EventWorkerStart, Worker: worker})
+}
+
+func (r *Recorder) WorkerFinish(worker WorkerInfo, items int) {
+	r.record(Event{Kind: EventWorkerFinish, Worker: worker, Count: items})
+}
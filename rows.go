@@ -0,0 +1,97 @@
+package enumerable
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/sdehm/go-enumerable/internal/dbfield"
+)
+
+// FromRows creates an Enumerable[T] that scans each row of rows into a T
+// Columns are matched to struct fields by the db struct tag, or the lower case field name when there is no tag,
+// a T that is not a struct, implements sql.Scanner or is a time.Time is scanned from a single column
+// Rows are read lazily and closed when the terminal operation returns, including when it stops early
+// Rows can only be read once, evaluating the Enumerable again fails with ErrConsumed
+// Scan errors and errors of the rows, such as a cancelled query context, stop the evaluation and are
+// returned by ToListErr and ForEachErr
+func FromRows[T any](rows *sql.Rows) Enumerable[T] {
+	var read atomic.Bool
+	return fromSource(func(s *scope) iterator[T] {
+		if read.Swap(true) {
+			s.fail(ErrConsumed)
+			return emptyIterator[T]
+		}
+		s.onClose(func() {
+			if err := rows.Close(); err != nil {
+				s.fail(err)
+			}
+		})
+		var targets func(*T) []any
+		return func() (T, bool) {
+			var v T
+			if s.err != nil {
+				return v, false
+			}
+			if !rows.Next() {
+				if err := rows.Err(); err != nil {
+					s.fail(err)
+				}
+				return v, false
+			}
+			if targets == nil {
+				var err error
+				if targets, err = scanTargets[T](rows); err != nil {
+					s.fail(err)
+					return v, false
+				}
+			}
+			if err := rows.Scan(targets(&v)...); err != nil {
+				s.fail(err)
+				return v, false
+			}
+			return v, true
+		}
+	})
+}
+
+var scannerType = reflect.TypeFor[sql.Scanner]()
+
+// scanTargets returns a function giving the pointers rows.Scan writes each column of a row to in a T
+func scanTargets[T any](rows *sql.Rows) (func(*T) []any, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(scannerType) || t == reflect.TypeFor[time.Time]() {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("enumerable: scanning %d columns into %v, expected 1", len(columns), t)
+		}
+		return func(v *T) []any {
+			return []any{v}
+		}, nil
+	}
+	fields := map[string][]int{}
+	for _, f := range dbfield.Fields(t) {
+		fields[f.Column] = f.Index
+	}
+	indexes := make([][]int, len(columns))
+	for i, c := range columns {
+		index, ok := fields[c]
+		if !ok {
+			return nil, fmt.Errorf("enumerable: no field of %v for column %q", t, c)
+		}
+		indexes[i] = index
+	}
+	return func(v *T) []any {
+		value := reflect.ValueOf(v).Elem()
+		targets := make([]any, len(indexes))
+		for i, index := range indexes {
+			targets[i] = value.FieldByIndex(index).Addr().Interface()
+		}
+		return targets
+	}, nil
+}
@@ -0,0 +1,248 @@
+package enumerable
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeTable is the result every query on a fake database returns
+type fakeTable struct {
+	columns []string
+	rows    [][]driver.Value
+	// err is returned instead of the row after the last row
+	err    error
+	closed atomic.Bool
+}
+
+var fakeTables sync.Map
+
+type fakeDriver struct{}
+
+func init() {
+	sql.Register("enumerable-fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	table, ok := fakeTables.Load(name)
+	if !ok {
+		return nil, errors.New("fake: unknown table " + name)
+	}
+	return fakeConn{table.(*fakeTable)}, nil
+}
+
+type fakeConn struct {
+	table *fakeTable
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) {
+	return fakeStmt(c), nil
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fake: transactions are not supported")
+}
+
+type fakeStmt struct {
+	table *fakeTable
+}
+
+func (fakeStmt) Close() error {
+	return nil
+}
+
+func (fakeStmt) NumInput() int {
+	return -1
+}
+
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("fake: exec is not supported")
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{table: s.table}, nil
+}
+
+type fakeRows struct {
+	table *fakeTable
+	next  int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.table.columns
+}
+
+func (r *fakeRows) Close() error {
+	r.table.closed.Store(true)
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next == len(r.table.rows) {
+		if r.table.err != nil {
+			return r.table.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.table.rows[r.next])
+	r.next++
+	return nil
+}
+
+// queryFake returns the rows of a fake database holding table
+func queryFake(t *testing.T, ctx context.Context, table *fakeTable) *sql.Rows {
+	t.Helper()
+	fakeTables.Store(t.Name(), table)
+	t.Cleanup(func() { fakeTables.Delete(t.Name()) })
+	db, err := sql.Open("enumerable-fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	rows, err := db.QueryContext(ctx, "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+type row struct {
+	ID      int64 `db:"id"`
+	Name    string
+	Ignored string `db:"-"`
+}
+
+func usersTable() *fakeTable {
+	return &fakeTable{
+		columns: []string{"id", "name"},
+		rows:    [][]driver.Value{{int64(1), "Ada"}, {int64(2), "Grace"}, {int64(3), "Barbara"}},
+	}
+}
+
+func TestFromRows(t *testing.T) {
+	table := usersTable()
+	result, err := FromRows[row](queryFake(t, context.Background(), table)).ToListErr()
+	expected := []row{{1, "Ada", ""}, {2, "Grace", ""}, {3, "Barbara", ""}}
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if !table.closed.Load() {
+		t.Errorf("Expected rows to be closed")
+	}
+}
+
+func TestFromRowsScalar(t *testing.T) {
+	table := &fakeTable{columns: []string{"name"}, rows: [][]driver.Value{{"Ada"}, {"Grace"}}}
+	result := FromRows[string](queryFake(t, context.Background(), table)).ToList()
+	expected := []string{"Ada", "Grace"}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestFromRowsTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	table := &fakeTable{columns: []string{"created"}, rows: [][]driver.Value{{now}}}
+	result := FromRows[time.Time](queryFake(t, context.Background(), table)).ToList()
+
+	if len(result) != 1 || !result[0].Equal(now) {
+		t.Errorf("Expected [%v], got %v", now, result)
+	}
+}
+
+func TestFromRowsEarlyStop(t *testing.T) {
+	table := usersTable()
+	result := FromRows[row](queryFake(t, context.Background(), table)).Take(1).ToList()
+
+	if len(result) != 1 {
+		t.Errorf("Expected 1 value, got %d", len(result))
+	}
+	if !table.closed.Load() {
+		t.Errorf("Expected rows to be closed")
+	}
+}
+
+func TestFromRowsScanError(t *testing.T) {
+	table := usersTable()
+	table.rows[1][0] = "two"
+	result, err := FromRows[row](queryFake(t, context.Background(), table)).ToListErr()
+
+	if err == nil {
+		t.Errorf("Expected a scan error, got %v", result)
+	}
+	if !table.closed.Load() {
+		t.Errorf("Expected rows to be closed")
+	}
+}
+
+func TestFromRowsUnknownColumn(t *testing.T) {
+	table := usersTable()
+	table.columns = []string{"id", "email"}
+	err := FromRows[row](queryFake(t, context.Background(), table)).ForEachErr(func(row) {})
+
+	if err == nil || err.Error() != `enumerable: no field of enumerable.row for column "email"` {
+		t.Errorf("Expected an unknown column error, got %v", err)
+	}
+}
+
+func TestFromRowsDriverError(t *testing.T) {
+	table := usersTable()
+	table.err = errors.New("connection reset")
+	count := 0
+	err := FromRows[row](queryFake(t, context.Background(), table)).ForEachErr(func(row) { count++ })
+
+	if err != table.err {
+		t.Errorf("Expected %v, got %v", table.err, err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 values before the error, got %d", count)
+	}
+}
+
+func TestFromRowsCancelled(t *testing.T) {
+	table := usersTable()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count := 0
+	err := FromRows[row](queryFake(t, ctx, table)).ForEachErr(func(row) {
+		count++
+		cancel()
+		// rows are closed by database/sql in the background once the context is done
+		for !table.closed.Load() {
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 value before cancellation, got %d", count)
+	}
+}
+
+func TestFromRowsConsumed(t *testing.T) {
+	e := FromRows[row](queryFake(t, context.Background(), usersTable()))
+	e.ToList()
+	_, err := e.ToListErr()
+
+	if !errors.Is(err, ErrConsumed) {
+		t.Errorf("Expected %v, got %v", ErrConsumed, err)
+	}
+}
@@ -0,0 +1,31 @@
+package enumerable
+
+import "iter"
+
+// FromSeq creates an Enumerable[T] from a sequence, which is iterated again on each evaluation
+// Iteration of seq stops when the terminal operation returns, including when it stops early
+func FromSeq[T any](seq iter.Seq[T]) Enumerable[T] {
+	return fromSource(func(s *scope) iterator[T] {
+		next, stop := iter.Pull(seq)
+		s.onClose(stop)
+		return next
+	})
+}
+
+// FromSeqErr creates an Enumerable[T] from a sequence of values and errors like FromSeq
+// The first error stops the evaluation and is returned by ToListErr and ForEachErr
+func FromSeqErr[T any](seq iter.Seq2[T, error]) Enumerable[T] {
+	return fromSource(func(s *scope) iterator[T] {
+		next, stop := iter.Pull2(seq)
+		s.onClose(stop)
+		return func() (T, bool) {
+			v, err, ok := next()
+			if err != nil {
+				s.fail(err)
+				var zero T
+				return zero, false
+			}
+			return v, ok
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package enumerable
+
+import (
+	"errors"
+	"iter"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestFromSeq(t *testing.T) {
+	e := FromSeq(slices.Values([]int{1, 2, 3, 4}))
+	result := e.Filter(func(i int) bool { return i%2 == 0 }).ToList()
+	expected := []int{2, 4}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if count := e.Count(); count != 4 {
+		t.Errorf("Expected the sequence to be iterated again, got %d values", count)
+	}
+}
+
+func TestFromSeqStopsEarly(t *testing.T) {
+	stopped := false
+	seq := func(yield func(int) bool) {
+		defer func() { stopped = true }()
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	result := FromSeq(seq).Take(3).ToList()
+
+	if !reflect.DeepEqual(result, []int{0, 1, 2}) {
+		t.Errorf("Expected [0 1 2], got %v", result)
+	}
+	if !stopped {
+		t.Errorf("Expected the sequence to be stopped")
+	}
+}
+
+func TestFromSeqErr(t *testing.T) {
+	failure := errors.New("failure")
+	var seq iter.Seq2[int, error] = func(yield func(int, error) bool) {
+		_ = yield(1, nil) && yield(2, nil) && yield(0, failure) && yield(3, nil)
+	}
+	var result []int
+	err := FromSeqErr(seq).ForEachErr(func(i int) { result = append(result, i) })
+
+	if err != failure {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+	if !reflect.DeepEqual(result, []int{1, 2}) {
+		t.Errorf("Expected [1 2], got %v", result)
+	}
+}
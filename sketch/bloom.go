@@ -0,0 +1,94 @@
+package sketch
+
+import (
+	"math"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+// BloomFilter tests whether a value may have been added to it
+// Contains never returns false for an added value but may return true for a value that was not added
+type BloomFilter[T comparable] struct {
+	bits   []uint64
+	size   uint64
+	hashes int
+}
+
+// NewBloomFilter creates an empty BloomFilter sized for n values with the given false positive rate
+// Panics if n is less than 1 or the false positive rate is not between 0 and 1
+func NewBloomFilter[T comparable](n int, falsePositiveRate float64) *BloomFilter[T] {
+	if n < 1 || falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic("sketch: invalid bloom filter parameters")
+	}
+	size := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	hashes := max(1, int(math.Round(float64(size)/float64(n)*math.Ln2)))
+	return &BloomFilter[T]{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// Add a value to the BloomFilter
+func (b *BloomFilter[T]) Add(v T) {
+	b.positions(v, func(i uint64) bool {
+		b.bits[i/64] |= 1 << (i % 64)
+		return true
+	})
+}
+
+// Contains returns false if the value was definitely not added and true if it may have been
+func (b *BloomFilter[T]) Contains(v T) bool {
+	return b.positions(v, func(i uint64) bool {
+		return b.bits[i/64]&(1<<(i%64)) != 0
+	})
+}
+
+// Merge the values of another BloomFilter into this one
+// Returns ErrIncompatible if the filters were created with different parameters
+func (b *BloomFilter[T]) Merge(o *BloomFilter[T]) error {
+	if b.size != o.size || b.hashes != o.hashes {
+		return ErrIncompatible
+	}
+	for i, word := range o.bits {
+		b.bits[i] |= word
+	}
+	return nil
+}
+
+// positions calls f with each bit position for the value until f returns false
+// Positions are derived from a single hash with double hashing
+func (b *BloomFilter[T]) positions(v T, f func(uint64) bool) bool {
+	h := hash(v)
+	h1, h2 := h&math.MaxUint32, h>>32|1
+	for i := 0; i < b.hashes; i++ {
+		if !f((h1 + uint64(i)*h2) % b.size) {
+			return false
+		}
+	}
+	return true
+}
+
+// CollectBloomFilter adds the values of the Enumerable[T] to a new BloomFilter sized for n values
+// Panics if n is less than 1 or the false positive rate is not between 0 and 1
+func CollectBloomFilter[T comparable](e enumerable.Enumerable[T], n int, falsePositiveRate float64) *BloomFilter[T] {
+	b := NewBloomFilter[T](n, falsePositiveRate)
+	e.ForEach(b.Add)
+	return b
+}
+
+// CollectBloomFilterParallel adds the values of the Enumerable[T] to a BloomFilter per worker and merges them
+// Panics if n is less than 1 or the false positive rate is not between 0 and 1
+func CollectBloomFilterParallel[T comparable](e enumerable.Enumerable[T], n int, falsePositiveRate float64, numWorkers ...int) *BloomFilter[T] {
+	seed := func() *BloomFilter[T] { return NewBloomFilter[T](n, falsePositiveRate) }
+	add := func(b *BloomFilter[T], v T) *BloomFilter[T] {
+		b.Add(v)
+		return b
+	}
+	merge := func(b *BloomFilter[T], o *BloomFilter[T]) *BloomFilter[T] {
+		// parameters always match as every filter comes from seed
+		b.Merge(o)
+		return b
+	}
+	return enumerable.FoldParallel(e, seed, add, merge, numWorkers...)
+}
@@ -0,0 +1,60 @@
+package sketch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	b := CollectBloomFilter(sequence(0, 1000), 1000, 0.01)
+
+	for i := 0; i < 1000; i++ {
+		if !b.Contains(i) {
+			t.Fatalf("Expected %d to be contained", i)
+		}
+	}
+	falsePositives := 0
+	for i := 1000; i < 11000; i++ {
+		if b.Contains(i) {
+			falsePositives++
+		}
+	}
+	// expect about 100 false positives
+	if falsePositives > 200 {
+		t.Errorf("Expected about 1%% false positives, got %d in 10000", falsePositives)
+	}
+}
+
+func TestBloomFilterMerge(t *testing.T) {
+	a := CollectBloomFilter(sequence(0, 100), 200, 0.01)
+	b := CollectBloomFilter(sequence(100, 100), 200, 0.01)
+
+	if err := a.Merge(b); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if !a.Contains(5) || !a.Contains(150) {
+		t.Errorf("Expected merged filter to contain values from both filters")
+	}
+	if err := a.Merge(NewBloomFilter[int](10, 0.01)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Expected %v, got %v", ErrIncompatible, err)
+	}
+}
+
+func TestCollectBloomFilterParallel(t *testing.T) {
+	b := CollectBloomFilterParallel(sequence(0, 1000), 1000, 0.01, 4)
+
+	for i := 0; i < 1000; i++ {
+		if !b.Contains(i) {
+			t.Fatalf("Expected %d to be contained", i)
+		}
+	}
+}
+
+func TestBloomFilterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic")
+		}
+	}()
+	NewBloomFilter[int](10, 1)
+}
@@ -0,0 +1,113 @@
+package sketch
+
+import (
+	"math"
+	"math/bits"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+const (
+	// MinPrecision is the smallest precision accepted by NewHyperLogLog
+	MinPrecision = 4
+	// MaxPrecision is the largest precision accepted by NewHyperLogLog
+	MaxPrecision = 18
+)
+
+// HyperLogLog estimates the number of distinct values added to it
+// A precision of p uses 2^p bytes with a standard error of about 1.04/sqrt(2^p)
+type HyperLogLog[T comparable] struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog creates an empty HyperLogLog
+// Panics if precision is outside of [MinPrecision, MaxPrecision]
+func NewHyperLogLog[T comparable](precision int) *HyperLogLog[T] {
+	if precision < MinPrecision || precision > MaxPrecision {
+		panic("sketch: hyperloglog precision out of range")
+	}
+	return &HyperLogLog[T]{
+		precision: uint8(precision),
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+// Add a value to the HyperLogLog
+func (h *HyperLogLog[T]) Add(v T) {
+	x := hash(v)
+	index := x >> (64 - h.precision)
+	// the guard bit bounds the rank when the remaining bits are all zero
+	rest := x<<h.precision | 1<<(h.precision-1)
+	rank := uint8(bits.LeadingZeros64(rest)) + 1
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// Merge the values of another HyperLogLog into this one
+// Returns ErrIncompatible if the precisions differ
+func (h *HyperLogLog[T]) Merge(o *HyperLogLog[T]) error {
+	if h.precision != o.precision {
+		return ErrIncompatible
+	}
+	for i, r := range o.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+	return nil
+}
+
+// Count returns the estimated number of distinct values added to the HyperLogLog
+func (h *HyperLogLog[T]) Count() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := alpha(len(h.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// linear counting is more accurate for small cardinalities
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// ApproxCountDistinct estimates the number of distinct values in the Enumerable[T] using a HyperLogLog
+// Panics if precision is outside of [MinPrecision, MaxPrecision]
+func ApproxCountDistinct[T comparable](e enumerable.Enumerable[T], precision int) uint64 {
+	h := NewHyperLogLog[T](precision)
+	e.ForEach(h.Add)
+	return h.Count()
+}
+
+// ApproxCountDistinctParallel estimates the number of distinct values in the Enumerable[T] with a HyperLogLog per worker
+// Panics if precision is outside of [MinPrecision, MaxPrecision]
+func ApproxCountDistinctParallel[T comparable](e enumerable.Enumerable[T], precision int, numWorkers ...int) uint64 {
+	seed := func() *HyperLogLog[T] { return NewHyperLogLog[T](precision) }
+	add := func(h *HyperLogLog[T], v T) *HyperLogLog[T] {
+		h.Add(v)
+		return h
+	}
+	merge := func(h *HyperLogLog[T], o *HyperLogLog[T]) *HyperLogLog[T] {
+		// precisions always match as every HyperLogLog comes from seed
+		h.Merge(o)
+		return h
+	}
+	return enumerable.FoldParallel(e, seed, add, merge, numWorkers...).Count()
+}
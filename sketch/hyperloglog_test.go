@@ -0,0 +1,75 @@
+package sketch
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+func sequence(start, n int) enumerable.Enumerable[int] {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = start + i
+	}
+	return enumerable.New(values)
+}
+
+func withinError(t *testing.T, expected, result uint64, tolerance float64) {
+	t.Helper()
+	if math.Abs(float64(result)-float64(expected)) > float64(expected)*tolerance {
+		t.Errorf("Expected about %d, got %d", expected, result)
+	}
+}
+
+func TestApproxCountDistinct(t *testing.T) {
+	// each value appears twice
+	e := enumerable.Concat(sequence(0, 50000), sequence(0, 50000))
+	result := ApproxCountDistinct(e, 14)
+
+	withinError(t, 50000, result, 0.03)
+}
+
+func TestApproxCountDistinctSmall(t *testing.T) {
+	e := enumerable.New([]string{"a", "b", "c", "a"})
+	result := ApproxCountDistinct(e, 10)
+
+	if result != 3 {
+		t.Errorf("Expected %d, got %d", 3, result)
+	}
+	if result := ApproxCountDistinct(enumerable.New([]string{}), 10); result != 0 {
+		t.Errorf("Expected %d, got %d", 0, result)
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	a := NewHyperLogLog[int](12)
+	b := NewHyperLogLog[int](12)
+	sequence(0, 20000).ForEach(a.Add)
+	sequence(10000, 20000).ForEach(b.Add)
+
+	if err := a.Merge(b); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	withinError(t, 30000, a.Count(), 0.05)
+	if err := a.Merge(NewHyperLogLog[int](10)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Expected %v, got %v", ErrIncompatible, err)
+	}
+}
+
+func TestApproxCountDistinctParallel(t *testing.T) {
+	e := enumerable.Concat(sequence(0, 40000), sequence(20000, 40000))
+	result := ApproxCountDistinctParallel(e, 14, 4)
+
+	withinError(t, 60000, result, 0.03)
+}
+
+func TestHyperLogLogPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic")
+		}
+	}()
+	NewHyperLogLog[int](MaxPrecision + 1)
+}
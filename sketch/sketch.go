@@ -0,0 +1,20 @@
+// Package sketch provides mergeable probabilistic summaries of Enumerables that use bounded memory
+//
+// Sketches built in the same process can be merged, which allows them to be
+// computed per worker in parallel and combined
+package sketch
+
+import (
+	"errors"
+	"hash/maphash"
+)
+
+// ErrIncompatible is returned when merging sketches that were created with different parameters
+var ErrIncompatible = errors.New("sketch: cannot merge sketches with different parameters")
+
+// seed is shared by all sketches so that sketches in the same process hash values identically
+var seed = maphash.MakeSeed()
+
+func hash[T comparable](v T) uint64 {
+	return maphash.Comparable(seed, v)
+}
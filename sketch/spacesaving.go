@@ -0,0 +1,165 @@
+package sketch
+
+import (
+	"container/heap"
+	"sort"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+// Counter is an estimated count of a value
+// The true count is between Count-Error and Count
+type Counter[T comparable] struct {
+	Value T
+	Count uint64
+	Error uint64
+}
+
+// SpaceSaving tracks the most frequent values added to it using at most k counters
+// Any value that occurs more than n/k times in n values is guaranteed to be tracked
+type SpaceSaving[T comparable] struct {
+	k        int
+	counters []*Counter[T]
+	index    map[T]int
+}
+
+// NewSpaceSaving creates an empty SpaceSaving sketch with k counters
+// Panics if k is less than 1
+func NewSpaceSaving[T comparable](k int) *SpaceSaving[T] {
+	if k < 1 {
+		panic("sketch: number of counters must be positive")
+	}
+	return &SpaceSaving[T]{k: k, index: map[T]int{}}
+}
+
+// Add a value to the SpaceSaving sketch
+// When all counters are in use the smallest counter is reassigned to the value
+func (s *SpaceSaving[T]) Add(v T) {
+	if i, ok := s.index[v]; ok {
+		s.counters[i].Count++
+		heap.Fix(s, i)
+		return
+	}
+	if len(s.counters) < s.k {
+		heap.Push(s, &Counter[T]{v, 1, 0})
+		return
+	}
+	smallest := s.counters[0]
+	delete(s.index, smallest.Value)
+	s.index[v] = 0
+	s.counters[0] = &Counter[T]{v, smallest.Count + 1, smallest.Count}
+	heap.Fix(s, 0)
+}
+
+// Merge the counters of another SpaceSaving sketch into this one
+// Values missing from a full sketch are assumed to have its smallest count
+// Returns ErrIncompatible if the number of counters differ
+func (s *SpaceSaving[T]) Merge(o *SpaceSaving[T]) error {
+	if s.k != o.k {
+		return ErrIncompatible
+	}
+	combined := map[T]Counter[T]{}
+	for _, c := range s.counters {
+		combined[c.Value] = *c
+	}
+	for _, c := range o.counters {
+		current, ok := combined[c.Value]
+		if !ok {
+			current = Counter[T]{Value: c.Value, Count: s.floor(), Error: s.floor()}
+		}
+		current.Count += c.Count
+		current.Error += c.Error
+		combined[c.Value] = current
+	}
+	for v, c := range combined {
+		if _, ok := o.index[v]; !ok {
+			c.Count += o.floor()
+			c.Error += o.floor()
+			combined[v] = c
+		}
+	}
+
+	all := make([]Counter[T], 0, len(combined))
+	for _, c := range combined {
+		all = append(all, c)
+	}
+	sortCounters(all)
+	if len(all) > s.k {
+		all = all[:s.k]
+	}
+	s.counters = s.counters[:0]
+	s.index = map[T]int{}
+	for _, c := range all {
+		heap.Push(s, &Counter[T]{c.Value, c.Count, c.Error})
+	}
+	return nil
+}
+
+// Top returns the tracked counters ordered from most to least frequent
+func (s *SpaceSaving[T]) Top() []Counter[T] {
+	result := make([]Counter[T], len(s.counters))
+	for i, c := range s.counters {
+		result[i] = *c
+	}
+	sortCounters(result)
+	return result
+}
+
+// floor returns the smallest count of a full sketch, which bounds the count of any untracked value
+func (s *SpaceSaving[T]) floor() uint64 {
+	if len(s.counters) < s.k {
+		return 0
+	}
+	return s.counters[0].Count
+}
+
+func sortCounters[T comparable](counters []Counter[T]) {
+	sort.SliceStable(counters, func(i, j int) bool { return counters[i].Count > counters[j].Count })
+}
+
+func (s *SpaceSaving[T]) Len() int           { return len(s.counters) }
+func (s *SpaceSaving[T]) Less(i, j int) bool { return s.counters[i].Count < s.counters[j].Count }
+
+func (s *SpaceSaving[T]) Swap(i, j int) {
+	s.counters[i], s.counters[j] = s.counters[j], s.counters[i]
+	s.index[s.counters[i].Value] = i
+	s.index[s.counters[j].Value] = j
+}
+
+func (s *SpaceSaving[T]) Push(x any) {
+	c := x.(*Counter[T])
+	s.index[c.Value] = len(s.counters)
+	s.counters = append(s.counters, c)
+}
+
+func (s *SpaceSaving[T]) Pop() any {
+	last := s.counters[len(s.counters)-1]
+	s.counters = s.counters[:len(s.counters)-1]
+	delete(s.index, last.Value)
+	return last
+}
+
+// HeavyHitters returns estimated counts of the most frequent values in the Enumerable[T] using k counters
+// Counters are ordered from most to least frequent
+// Panics if k is less than 1
+func HeavyHitters[T comparable](e enumerable.Enumerable[T], k int) []Counter[T] {
+	s := NewSpaceSaving[T](k)
+	e.ForEach(s.Add)
+	return s.Top()
+}
+
+// HeavyHittersParallel returns estimated counts of the most frequent values in the Enumerable[T] with a sketch per worker
+// Panics if k is less than 1
+func HeavyHittersParallel[T comparable](e enumerable.Enumerable[T], k int, numWorkers ...int) []Counter[T] {
+	seed := func() *SpaceSaving[T] { return NewSpaceSaving[T](k) }
+	add := func(s *SpaceSaving[T], v T) *SpaceSaving[T] {
+		s.Add(v)
+		return s
+	}
+	merge := func(s *SpaceSaving[T], o *SpaceSaving[T]) *SpaceSaving[T] {
+		// sizes always match as every sketch comes from seed
+		s.Merge(o)
+		return s
+	}
+	return enumerable.FoldParallel(e, seed, add, merge, numWorkers...).Top()
+}
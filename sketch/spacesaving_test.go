@@ -0,0 +1,82 @@
+package sketch
+
+import (
+	"errors"
+	"testing"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+// skewed returns a stream where "a" appears 500 times, "b" 300 times and 1000 other values once
+func skewed() enumerable.Enumerable[string] {
+	values := []string{}
+	for i := 0; i < 1000; i++ {
+		values = append(values, string(rune(0x4e00+i)))
+		if i%2 == 0 {
+			values = append(values, "a")
+		}
+		if i%10 < 3 {
+			values = append(values, "b")
+		}
+	}
+	return enumerable.New(values)
+}
+
+func TestHeavyHitters(t *testing.T) {
+	result := HeavyHitters(skewed(), 20)
+
+	if len(result) != 20 {
+		t.Fatalf("Expected 20 counters, got %d", len(result))
+	}
+	if result[0].Value != "a" || result[1].Value != "b" {
+		t.Errorf("Expected a and b first, got %v and %v", result[0].Value, result[1].Value)
+	}
+	for _, c := range result[:2] {
+		expected := map[string]uint64{"a": 500, "b": 300}[c.Value]
+		if c.Count-c.Error > expected || c.Count < expected {
+			t.Errorf("Expected count of %s to bound %d, got %d with error %d", c.Value, expected, c.Count, c.Error)
+		}
+	}
+}
+
+func TestHeavyHittersExact(t *testing.T) {
+	e := enumerable.New([]int{1, 2, 1, 3, 1, 2})
+	result := HeavyHitters(e, 3)
+	expected := []Counter[int]{{1, 3, 0}, {2, 2, 0}, {3, 1, 0}}
+
+	for i, c := range result {
+		if c != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], c)
+		}
+	}
+}
+
+func TestSpaceSavingMerge(t *testing.T) {
+	a := NewSpaceSaving[string](3)
+	b := NewSpaceSaving[string](3)
+	for _, v := range []string{"x", "x", "y", "z", "x"} {
+		a.Add(v)
+	}
+	for _, v := range []string{"y", "y", "x", "w"} {
+		b.Add(v)
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	result := a.Top()
+	if len(result) != 3 || result[0].Value != "x" || result[0].Count != 4 {
+		t.Errorf("Expected x with count 4 first, got %v", result)
+	}
+	if err := a.Merge(NewSpaceSaving[string](4)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Expected %v, got %v", ErrIncompatible, err)
+	}
+}
+
+func TestHeavyHittersParallel(t *testing.T) {
+	result := HeavyHittersParallel(skewed(), 50, 4)
+
+	if result[0].Value != "a" || result[1].Value != "b" {
+		t.Errorf("Expected a and b first, got %v and %v", result[0].Value, result[1].Value)
+	}
+}
@@ -0,0 +1,174 @@
+package enumerable
+
+import (
+	"context"
+	"slices"
+)
+
+// DefaultMaxInMemory is the number of values external operations hold in memory when no limit is set
+const DefaultMaxInMemory = 1 << 16
+
+// ExternalOptions configures operations that spill values to temporary files when they exceed a memory budget
+type ExternalOptions[T any] struct {
+	// MaxInMemory is the maximum number of values held in memory at once, DefaultMaxInMemory if not positive
+	MaxInMemory int
+	// TempDir is the directory for temporary files, the default temporary directory if empty
+	TempDir string
+	// Codec writes values to temporary files, GobCodec if nil
+	Codec Codec[T]
+	// Partitions is the number of temporary files hash partitioned operations spread values across, 16 if not positive
+	Partitions int
+	// MaxOpenFiles is the maximum number of sorted runs read at once when merging, 16 if less than 2
+	// Sorts with more runs merge them in several passes
+	MaxOpenFiles int
+	// Context stops the operation when cancelled, the error is returned by ToListErr and ForEachErr
+	Context context.Context
+}
+
+func (o ExternalOptions[T]) withDefaults() ExternalOptions[T] {
+	if o.MaxInMemory <= 0 {
+		o.MaxInMemory = DefaultMaxInMemory
+	}
+	if o.Partitions <= 0 {
+		o.Partitions = 16
+	}
+	if o.MaxOpenFiles < 2 {
+		o.MaxOpenFiles = 16
+	}
+	if o.Codec == nil {
+		o.Codec = GobCodec[T]{}
+	}
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	return o
+}
+
+// OrderBy sorts the Enumerable[T] by cmp, keeping equal values in their original order
+// cmp returns a negative number when a sorts before b, zero when equal and a positive number otherwise
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) OrderBy(cmp func(a, b T) int) Enumerable[T] {
+	return e.lazy(OpOrderBy, func(e Enumerable[T]) Enumerable[T] {
+		values := slices.Clone(e.values)
+		slices.SortStableFunc(values, cmp)
+		return New(values)
+	}).describe(func(s *stage[T]) { s.cmp = cmp })
+}
+
+// OrderByExternal sorts the Enumerable[T] by cmp like OrderBy while holding at most options.MaxInMemory values in memory
+// Values are sorted in runs that are written to temporary files and merged lazily as the result is read
+// At most options.MaxOpenFiles runs are read at once, more runs are first merged into longer runs
+// Temporary files are removed when the terminal operation returns, including when it stops early or the context is cancelled
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) OrderByExternal(cmp func(a, b T) int, options ExternalOptions[T]) Enumerable[T] {
+	options = options.withDefaults()
+	return e.streamScoped("OrderByExternal", func(s *scope, next iterator[T]) iterator[T] {
+		var sorted iterator[T]
+		return func() (T, bool) {
+			if sorted == nil {
+				sorted = externalSort(s, next, cmp, options)
+			}
+			if err := options.Context.Err(); err != nil {
+				s.fail(err)
+			}
+			if s.err != nil {
+				var zero T
+				return zero, false
+			}
+			return sorted()
+		}
+	})
+}
+
+// externalSort spills sorted runs of the values to temporary files and returns an iterator merging the runs
+// The last run is kept in memory
+func externalSort[T any](s *scope, next iterator[T], cmp func(a, b T) int, options ExternalOptions[T]) iterator[T] {
+	var spills []*spill[T]
+	s.onClose(func() {
+		for _, f := range spills {
+			f.remove()
+		}
+	})
+
+	buffer := make([]T, 0, options.MaxInMemory)
+	for v, ok := next(); ok; v, ok = next() {
+		buffer = append(buffer, v)
+		if len(buffer) < options.MaxInMemory {
+			continue
+		}
+		if err := options.Context.Err(); err != nil {
+			s.fail(err)
+			return emptyIterator[T]
+		}
+		slices.SortStableFunc(buffer, cmp)
+		f, err := writeRun(s, options, New(buffer).iterate(s))
+		if f != nil {
+			spills = append(spills, f)
+		}
+		if err != nil {
+			s.fail(err)
+			return emptyIterator[T]
+		}
+		buffer = buffer[:0]
+	}
+	slices.SortStableFunc(buffer, cmp)
+
+	// Merge consecutive runs so the result stays stable, leaving room for the run in memory
+	runs := spills
+	for len(runs) >= options.MaxOpenFiles {
+		var merged []*spill[T]
+		for start := 0; start < len(runs); start += options.MaxOpenFiles {
+			group := runs[start:min(start+options.MaxOpenFiles, len(runs))]
+			if len(group) == 1 {
+				merged = append(merged, group[0])
+				continue
+			}
+			if err := options.Context.Err(); err != nil {
+				s.fail(err)
+				return emptyIterator[T]
+			}
+			iterators := make([]iterator[T], len(group))
+			for i, f := range group {
+				iterators[i] = f.iterate(s)
+			}
+			f, err := writeRun(s, options, mergeIterators(cmp, iterators))
+			if f != nil {
+				spills = append(spills, f)
+				merged = append(merged, f)
+			}
+			if err != nil {
+				s.fail(err)
+				return emptyIterator[T]
+			}
+			for _, f := range group {
+				f.remove()
+			}
+		}
+		runs = merged
+	}
+
+	iterators := make([]iterator[T], 0, len(runs)+1)
+	for _, f := range runs {
+		iterators = append(iterators, f.iterate(s))
+	}
+	iterators = append(iterators, New(buffer).iterate(s))
+	return mergeIterators(cmp, iterators)
+}
+
+// writeRun writes the values of next to a new temporary file and closes it
+// The file is returned for removal even if writing fails
+func writeRun[T any](s *scope, options ExternalOptions[T], next iterator[T]) (*spill[T], error) {
+	f, err := createSpill(options.TempDir, options.Codec)
+	if err != nil {
+		return nil, err
+	}
+	for v, ok := next(); ok; v, ok = next() {
+		if err := f.write(v); err != nil {
+			return f, err
+		}
+	}
+	if s.err != nil {
+		return f, s.err
+	}
+	return f, f.close()
+}
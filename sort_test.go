@@ -0,0 +1,240 @@
+package enumerable
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type sortRecord struct {
+	Key   int
+	Order int
+}
+
+func sortRecords(n int) []sortRecord {
+	records := make([]sortRecord, n)
+	for i := range records {
+		records[i] = sortRecord{(i * 7919) % 13, i}
+	}
+	return records
+}
+
+func compareKeys(a, b sortRecord) int {
+	return cmp.Compare(a.Key, b.Key)
+}
+
+func expectedSort(records []sortRecord) []sortRecord {
+	expected := slices.Clone(records)
+	slices.SortStableFunc(expected, compareKeys)
+	return expected
+}
+
+func tempFiles(t *testing.T, dir string) int {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(entries)
+}
+
+func TestOrderBy(t *testing.T) {
+	records := sortRecords(50)
+	result := New(records).OrderBy(compareKeys).ToList()
+
+	if !reflect.DeepEqual(result, expectedSort(records)) {
+		t.Errorf("Expected %v, got %v", expectedSort(records), result)
+	}
+	if records[1].Order != 1 {
+		t.Errorf("Expected source to be unchanged, got %v", records[:2])
+	}
+}
+
+func TestOrderByExternal(t *testing.T) {
+	dir := t.TempDir()
+	records := sortRecords(100)
+	for _, codec := range []Codec[sortRecord]{GobCodec[sortRecord]{}, JSONCodec[sortRecord]{}} {
+		options := ExternalOptions[sortRecord]{MaxInMemory: 7, TempDir: dir, Codec: codec}
+		result, err := New(records).OrderByExternal(compareKeys, options).ToListErr()
+
+		if err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		if !reflect.DeepEqual(result, expectedSort(records)) {
+			t.Errorf("Expected %v, got %v", expectedSort(records), result)
+		}
+		if n := tempFiles(t, dir); n != 0 {
+			t.Errorf("Expected temporary files to be removed, found %d", n)
+		}
+	}
+}
+
+func TestOrderByExternalSpills(t *testing.T) {
+	dir := t.TempDir()
+	options := ExternalOptions[int]{MaxInMemory: 4, TempDir: dir}
+	e := New([]int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}).OrderByExternal(cmp.Compare[int], options)
+	files := 0
+	e.Take(1).ForEach(func(int) {
+		files = tempFiles(t, dir)
+	})
+
+	if files != 2 {
+		t.Errorf("Expected 2 spilled runs, got %d", files)
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Expected temporary files to be removed after stopping early, found %d", n)
+	}
+}
+
+// openFiles returns the number of files in dir the process has open
+func openFiles(t *testing.T, dir string) int {
+	t.Helper()
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("open files are not listed in /proc/self/fd")
+	}
+	n := 0
+	for _, fd := range fds {
+		target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name()))
+		if err == nil && filepath.Dir(target) == dir {
+			n++
+		}
+	}
+	return n
+}
+
+// sampledCodec records the most files open in dir whenever a value is encoded or decoded
+type sampledCodec struct {
+	t    *testing.T
+	dir  string
+	most *int
+}
+
+func (c sampledCodec) sample() {
+	*c.most = max(*c.most, openFiles(c.t, c.dir))
+}
+
+func (c sampledCodec) NewEncoder(w io.Writer) Encoder[int] {
+	return sampledEncoder{c, GobCodec[int]{}.NewEncoder(w)}
+}
+
+func (c sampledCodec) NewDecoder(r io.Reader) Decoder[int] {
+	return sampledDecoder{c, GobCodec[int]{}.NewDecoder(r)}
+}
+
+type sampledEncoder struct {
+	codec sampledCodec
+	Encoder[int]
+}
+
+func (e sampledEncoder) Encode(v int) error {
+	e.codec.sample()
+	return e.Encoder.Encode(v)
+}
+
+type sampledDecoder struct {
+	codec sampledCodec
+	Decoder[int]
+}
+
+func (d sampledDecoder) Decode() (int, error) {
+	d.codec.sample()
+	return d.Decoder.Decode()
+}
+
+func TestOrderByExternalLimitsOpenFiles(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	most := 0
+	codec := sampledCodec{t, dir, &most}
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = (i * 7919) % 1000
+	}
+	options := ExternalOptions[int]{MaxInMemory: 2, TempDir: dir, Codec: codec, MaxOpenFiles: 8}
+	result, err := New(values).OrderByExternal(cmp.Compare[int], options).ToListErr()
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if !slices.IsSorted(result) || len(result) != len(values) {
+		t.Errorf("Expected %d sorted values, got %d sorted %v", len(values), len(result), slices.IsSorted(result))
+	}
+	// A merge pass reads MaxOpenFiles runs while writing the merged run
+	if most > 9 {
+		t.Errorf("Expected at most 9 open files, got %d", most)
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Expected temporary files to be removed, found %d", n)
+	}
+}
+
+func TestOrderByExternalInMemory(t *testing.T) {
+	dir := t.TempDir()
+	options := ExternalOptions[int]{MaxInMemory: 10, TempDir: dir}
+	result := New([]int{3, 1, 2}).OrderByExternal(cmp.Compare[int], options).ToList()
+
+	if !reflect.DeepEqual(result, []int{1, 2, 3}) {
+		t.Errorf("Expected %v, got %v", []int{1, 2, 3}, result)
+	}
+}
+
+func TestOrderByExternalCancelled(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	options := ExternalOptions[int]{MaxInMemory: 2, TempDir: dir, Context: ctx}
+	e := New([]int{5, 4, 3, 2, 1}).OrderByExternal(cmp.Compare[int], options)
+	err := e.ForEachErr(func(i int) {
+		cancel()
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Expected temporary files to be removed, found %d", n)
+	}
+}
+
+type failingCodec struct{}
+
+func (failingCodec) NewEncoder(w io.Writer) Encoder[int] { return failingEncoder{} }
+func (failingCodec) NewDecoder(r io.Reader) Decoder[int] { return nil }
+
+type failingEncoder struct{}
+
+var errEncode = errors.New("encode failed")
+
+func (failingEncoder) Encode(int) error { return errEncode }
+
+func TestOrderByExternalCodecError(t *testing.T) {
+	dir := t.TempDir()
+	options := ExternalOptions[int]{MaxInMemory: 1, TempDir: dir, Codec: failingCodec{}}
+	e := New([]int{2, 1}).OrderByExternal(cmp.Compare[int], options)
+
+	if _, err := e.ToListErr(); !errors.Is(err, errEncode) {
+		t.Errorf("Expected %v, got %v", errEncode, err)
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Expected temporary files to be removed, found %d", n)
+	}
+}
+
+func TestOrderByExternalPanicsWithoutErr(t *testing.T) {
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || !errors.Is(err, errEncode) {
+			t.Errorf("Expected panic with %v, got %v", errEncode, r)
+		}
+	}()
+	options := ExternalOptions[int]{MaxInMemory: 1, TempDir: t.TempDir(), Codec: failingCodec{}}
+	New([]int{2, 1}).OrderByExternal(cmp.Compare[int], options).ToList()
+}
@@ -0,0 +1,210 @@
+package enumerable
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+)
+
+// spill is a temporary file of encoded values
+// The file is open while values are written and is reopened by each iteration
+type spill[T any] struct {
+	name    string
+	file    *os.File
+	writer  *bufio.Writer
+	encoder Encoder[T]
+	codec   Codec[T]
+	count   int
+}
+
+// createSpill creates an empty temporary file in dir, or the default temporary directory if dir is empty
+func createSpill[T any](dir string, codec Codec[T]) (*spill[T], error) {
+	file, err := os.CreateTemp(dir, "enumerable-*")
+	if err != nil {
+		return nil, err
+	}
+	writer := bufio.NewWriter(file)
+	return &spill[T]{file.Name(), file, writer, codec.NewEncoder(writer), codec, 0}, nil
+}
+
+func (f *spill[T]) write(v T) error {
+	f.count++
+	return f.encoder.Encode(v)
+}
+
+// close flushes the values written and closes the file, keeping it on disk to be read
+func (f *spill[T]) close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.writer.Flush()
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	f.file = nil
+	return err
+}
+
+// iterate returns an iterator over the values written to the file
+// The file is opened for reading until the values are exhausted or the scope is released
+// Errors reading the file fail the scope
+func (f *spill[T]) iterate(s *scope) iterator[T] {
+	if err := f.close(); err != nil {
+		s.fail(err)
+		return emptyIterator[T]
+	}
+	file, err := os.Open(f.name)
+	if err != nil {
+		s.fail(err)
+		return emptyIterator[T]
+	}
+	done := func() {
+		if file != nil {
+			file.Close()
+			file = nil
+		}
+	}
+	s.onClose(done)
+	decoder := f.codec.NewDecoder(bufio.NewReader(file))
+	remaining := f.count
+	return func() (T, bool) {
+		if remaining == 0 || s.err != nil {
+			done()
+			var zero T
+			return zero, false
+		}
+		v, err := decoder.Decode()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			s.fail(err)
+			done()
+			return v, false
+		}
+		remaining--
+		return v, true
+	}
+}
+
+// remove closes and deletes the file
+func (f *spill[T]) remove() {
+	f.close()
+	os.Remove(f.name)
+}
+
+// partitions spreads values across temporary files by hash so that equal keys end up in the same file
+// Files are created on first use and removed when the scope is released
+type partitions[T any] struct {
+	files   []*spill[T]
+	count   int
+	options ExternalOptions[T]
+	// hash hashes the key of a value for a level of partitioning
+	hash  func(v T, level int) uint64
+	level int
+}
+
+func newPartitions[T any](s *scope, options ExternalOptions[T], hash func(v T, level int) uint64) *partitions[T] {
+	return newPartitionsLevel(s, options, hash, 0)
+}
+
+func newPartitionsLevel[T any](s *scope, options ExternalOptions[T], hash func(v T, level int) uint64, level int) *partitions[T] {
+	p := &partitions[T]{files: make([]*spill[T], options.Partitions), options: options, hash: hash, level: level}
+	s.onClose(p.remove)
+	return p
+}
+
+func (p *partitions[T]) write(v T) error {
+	return p.writeHash(p.hash(v, p.level), v)
+}
+
+func (p *partitions[T]) writeHash(hash uint64, v T) error {
+	p.count++
+	i := hash % uint64(len(p.files))
+	if p.files[i] == nil {
+		f, err := createSpill(p.options.TempDir, p.options.Codec)
+		if err != nil {
+			return err
+		}
+		p.files[i] = f
+	}
+	return p.files[i].write(v)
+}
+
+// remove deletes the files of the partitions
+func (p *partitions[T]) remove() {
+	for _, f := range p.files {
+		if f != nil {
+			f.remove()
+		}
+	}
+}
+
+// split writes the values of a partition holding too many values to partitions of the next level
+// Returns nil if the values all have the same hash, as they share a key and cannot be split
+func (p *partitions[T]) split(s *scope, f *spill[T]) *partitions[T] {
+	next := newPartitionsLevel(s, p.options, p.hash, p.level+1)
+	values := f.iterate(s)
+	var first uint64
+	same := true
+	for v, ok := values(); ok; v, ok = values() {
+		h := p.hash(v, p.level+1)
+		if next.count == 0 {
+			first = h
+		}
+		same = same && h == first
+		if err := next.writeHash(h, v); err != nil {
+			s.fail(err)
+			return next
+		}
+	}
+	if same {
+		next.remove()
+		return nil
+	}
+	f.remove()
+	return next
+}
+
+// eachPartition returns an iterator that reads the partitions one at a time, producing values with f
+// f is called with an iterator over the values of a single partition
+// Partitions holding more than options.MaxInMemory values are split again with a different hash until they fit,
+// or until their values share a key
+func eachPartition[T any, U any](s *scope, p *partitions[T], f func(iterator[T]) iterator[U]) iterator[U] {
+	for _, file := range p.files {
+		if file != nil {
+			if err := file.close(); err != nil {
+				s.fail(err)
+			}
+		}
+	}
+	i := 0
+	var current iterator[U]
+	return func() (U, bool) {
+		for {
+			if current != nil {
+				if v, ok := current(); ok {
+					return v, true
+				}
+				current = nil
+			}
+			for i < len(p.files) && p.files[i] == nil {
+				i++
+			}
+			if i == len(p.files) || s.err != nil {
+				var zero U
+				return zero, false
+			}
+			file := p.files[i]
+			i++
+			if file.count > p.options.MaxInMemory {
+				if next := p.split(s, file); next != nil {
+					current = eachPartition(s, next, f)
+					continue
+				}
+			}
+			current = f(file.iterate(s))
+		}
+	}
+}
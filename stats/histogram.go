@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"errors"
+	"math"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+// ErrInvalidBins is returned when a histogram is requested with fewer than one bin
+var ErrInvalidBins = errors.New("stats: number of bins must be positive")
+
+// Bin is a histogram bucket holding the values in [Lower, Upper)
+// The last bin of a histogram also includes its upper bound
+type Bin struct {
+	Lower float64
+	Upper float64
+	Count int
+}
+
+// Histogram counts the values of the Enumerable[T] into bins of equal width between the smallest and largest value
+// Returns ErrInvalidBins if bins is less than one, ErrNotFinite if a value is NaN or infinite
+// or enumerable.ErrEmpty if the Enumerable[T] is empty
+func Histogram[T enumerable.Number](e enumerable.Enumerable[T], bins int) ([]Bin, error) {
+	if bins < 1 {
+		return nil, ErrInvalidBins
+	}
+	values := e.ToList()
+	if len(values) == 0 {
+		return nil, enumerable.ErrEmpty
+	}
+	lower, upper := float64(values[0]), float64(values[0])
+	for _, v := range values {
+		if !finite(float64(v)) {
+			return nil, ErrNotFinite
+		}
+		lower = math.Min(lower, float64(v))
+		upper = math.Max(upper, float64(v))
+	}
+	return HistogramRange(enumerable.New(values), lower, upper, bins)
+}
+
+// HistogramRange counts the values of the Enumerable[T] into bins of equal width between lower and upper
+// Values outside of the range are ignored
+// Returns ErrInvalidBins if bins is less than one or ErrNotFinite if lower, upper or a value is NaN or infinite
+func HistogramRange[T enumerable.Number](e enumerable.Enumerable[T], lower, upper float64, bins int) ([]Bin, error) {
+	if bins < 1 {
+		return nil, ErrInvalidBins
+	}
+	if !finite(lower) || !finite(upper) {
+		return nil, ErrNotFinite
+	}
+	width := (upper - lower) / float64(bins)
+	result := make([]Bin, bins)
+	for i := range result {
+		result[i].Lower = lower + float64(i)*width
+		result[i].Upper = lower + float64(i+1)*width
+	}
+	result[bins-1].Upper = upper
+
+	var err error
+	e.ForEach(func(v T) {
+		x := float64(v)
+		if !finite(x) {
+			err = ErrNotFinite
+		}
+		if err != nil || x < lower || x > upper {
+			return
+		}
+		i := bins - 1
+		if width > 0 {
+			i = int((x - lower) / width)
+		}
+		if i >= bins {
+			i = bins - 1
+		}
+		result[i].Count++
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// maxAutoBins is the most bins HistogramAuto chooses with the Freedman-Diaconis rule
+const maxAutoBins = 1000
+
+// HistogramAuto counts the values of the Enumerable[T] into a number of bins chosen from the data
+// Uses the Freedman-Diaconis rule and falls back to Sturges' rule when the interquartile range is zero, or when
+// outliers would need more bins than there are values or more than 1000 bins
+// Returns ErrNotFinite if a value is NaN or infinite or enumerable.ErrEmpty if the Enumerable[T] is empty
+func HistogramAuto[T enumerable.Number](e enumerable.Enumerable[T]) ([]Bin, error) {
+	sorted, err := sortedValues(e)
+	if err != nil {
+		return nil, err
+	}
+	if len(sorted) == 0 {
+		return nil, enumerable.ErrEmpty
+	}
+	n := float64(len(sorted))
+	lower, upper := sorted[0], sorted[len(sorted)-1]
+
+	// Sturges' rule
+	bins := int(math.Ceil(math.Log2(n))) + 1
+	iqr := quantileSorted(sorted, 0.75) - quantileSorted(sorted, 0.25)
+	if iqr > 0 {
+		width := 2 * iqr / math.Cbrt(n)
+		// compare as floats since the count can overflow an int
+		if fd := math.Ceil((upper - lower) / width); fd <= math.Min(n, maxAutoBins) {
+			bins = int(fd)
+		}
+	}
+	if bins < 1 {
+		bins = 1
+	}
+	return HistogramRange(enumerable.New(sorted), lower, upper, bins)
+}
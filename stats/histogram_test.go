@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+func TestHistogram(t *testing.T) {
+	e := enumerable.New([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 10})
+	result, err := Histogram(e, 5)
+	expected := []Bin{{0, 2, 2}, {2, 4, 2}, {4, 6, 2}, {6, 8, 2}, {8, 10, 2}}
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d bins, got %d", len(expected), len(result))
+	}
+	for i, b := range result {
+		if b != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], b)
+		}
+	}
+}
+
+func TestHistogramSingleValue(t *testing.T) {
+	e := enumerable.New([]int{3, 3, 3})
+	result, _ := Histogram(e, 4)
+
+	if result[3].Count != 3 {
+		t.Errorf("Expected %d, got %d", 3, result[3].Count)
+	}
+}
+
+func TestHistogramRangeIgnoresOutliers(t *testing.T) {
+	e := enumerable.New([]float64{-1, 0.5, 1.5, 2, 3})
+	result, _ := HistogramRange(e, 0, 2, 2)
+
+	if result[0].Count != 1 || result[1].Count != 2 {
+		t.Errorf("Expected counts [1 2], got [%d %d]", result[0].Count, result[1].Count)
+	}
+}
+
+func TestHistogramAuto(t *testing.T) {
+	result, err := HistogramAuto(uniform(1000))
+	total := 0
+	for _, b := range result {
+		total += b.Count
+	}
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if len(result) < 2 {
+		t.Errorf("Expected several bins, got %d", len(result))
+	}
+	if total != 1000 {
+		t.Errorf("Expected %d, got %d", 1000, total)
+	}
+}
+
+func TestHistogramAutoOutlier(t *testing.T) {
+	values := make([]float64, 1000)
+	for i := range values {
+		values[i] = float64(i % 10)
+	}
+	values[0] = 1e9
+	result, err := HistogramAuto(enumerable.New(values))
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	// Sturges' rule gives ceil(log2(1000)) + 1 bins
+	if len(result) != 11 {
+		t.Errorf("Expected %d bins, got %d", 11, len(result))
+	}
+}
+
+func TestHistogramErrors(t *testing.T) {
+	if _, err := Histogram(enumerable.New([]int{1}), 0); !errors.Is(err, ErrInvalidBins) {
+		t.Errorf("Expected %v, got %v", ErrInvalidBins, err)
+	}
+	if _, err := Histogram(enumerable.New([]int{}), 2); !errors.Is(err, enumerable.ErrEmpty) {
+		t.Errorf("Expected %v, got %v", enumerable.ErrEmpty, err)
+	}
+	if _, err := HistogramAuto(enumerable.New([]int{})); !errors.Is(err, enumerable.ErrEmpty) {
+		t.Errorf("Expected %v, got %v", enumerable.ErrEmpty, err)
+	}
+}
+
+func TestHistogramNotFinite(t *testing.T) {
+	for _, x := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		e := enumerable.New([]float64{1, x, 3})
+		if _, err := Histogram(e, 3); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("Expected %v for %v, got %v", ErrNotFinite, x, err)
+		}
+		if _, err := HistogramAuto(e); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("Expected %v for %v, got %v", ErrNotFinite, x, err)
+		}
+		if _, err := HistogramRange(e, 0, 5, 3); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("Expected %v for %v, got %v", ErrNotFinite, x, err)
+		}
+		if _, err := HistogramRange(enumerable.New([]float64{1}), 0, x, 3); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("Expected %v for an upper bound of %v, got %v", ErrNotFinite, x, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+// Median returns the middle value of the Enumerable[T]
+// The two middle values are averaged when there is an even number of values
+// Returns ErrNotFinite if a value is NaN or infinite or enumerable.ErrEmpty if the Enumerable[T] is empty
+func Median[T enumerable.Number](e enumerable.Enumerable[T]) (float64, error) {
+	return Quantile(e, 0.5)
+}
+
+// Percentile returns the exact p-th percentile of the Enumerable[T] where p is between 0 and 100
+// Returns ErrInvalidQuantile if p is out of range, ErrNotFinite if a value is NaN or infinite
+// or enumerable.ErrEmpty if the Enumerable[T] is empty
+func Percentile[T enumerable.Number](e enumerable.Enumerable[T], p float64) (float64, error) {
+	if p < 0 || p > 100 {
+		return 0, ErrInvalidQuantile
+	}
+	return Quantile(e, p/100)
+}
+
+// Quantile returns the exact q-th quantile of the Enumerable[T] where q is between 0 and 1
+// Interpolates linearly between the closest ranks
+// All values are held in memory, use TDigest for large inputs
+// Returns ErrInvalidQuantile if q is out of range, ErrNotFinite if a value is NaN or infinite
+// or enumerable.ErrEmpty if the Enumerable[T] is empty
+func Quantile[T enumerable.Number](e enumerable.Enumerable[T], q float64) (float64, error) {
+	if q < 0 || q > 1 {
+		return 0, ErrInvalidQuantile
+	}
+	sorted, err := sortedValues(e)
+	if err != nil {
+		return 0, err
+	}
+	if len(sorted) == 0 {
+		return 0, enumerable.ErrEmpty
+	}
+	return quantileSorted(sorted, q), nil
+}
+
+// sortedValues returns a sorted copy of the values so the source slice is left untouched
+// Returns ErrNotFinite if a value is NaN or infinite
+func sortedValues[T enumerable.Number](e enumerable.Enumerable[T]) ([]float64, error) {
+	values := e.ToList()
+	sorted := make([]float64, len(values))
+	for i, v := range values {
+		sorted[i] = float64(v)
+		if !finite(sorted[i]) {
+			return nil, ErrNotFinite
+		}
+	}
+	sort.Float64s(sorted)
+	return sorted, nil
+}
+
+func finite(x float64) bool {
+	return !math.IsInf(x, 0) && !math.IsNaN(x)
+}
+
+func quantileSorted(sorted []float64, q float64) float64 {
+	rank := q * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	fraction := rank - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
+}
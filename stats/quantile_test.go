@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+func TestMedianOdd(t *testing.T) {
+	e := enumerable.New([]int{5, 1, 3})
+	result, _ := Median(e)
+
+	if result != 3 {
+		t.Errorf("Expected %f, got %f", 3.0, result)
+	}
+}
+
+func TestMedianEven(t *testing.T) {
+	e := enumerable.New([]int{4, 1, 3, 2})
+	result, _ := Median(e)
+
+	if result != 2.5 {
+		t.Errorf("Expected %f, got %f", 2.5, result)
+	}
+}
+
+func TestMedianDoesNotSortSource(t *testing.T) {
+	values := []int{3, 1, 2}
+	Median(enumerable.New(values))
+
+	if values[0] != 3 {
+		t.Errorf("Expected source to be unchanged, got %v", values)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	e := enumerable.New([]int{1, 2, 3, 4, 5})
+	tests := []struct {
+		p        float64
+		expected float64
+	}{
+		{0, 1},
+		{25, 2},
+		{50, 3},
+		{90, 4.6},
+		{100, 5},
+	}
+	for _, test := range tests {
+		result, err := Percentile(e, test.p)
+		if err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		if result != test.expected {
+			t.Errorf("Percentile %v: expected %f, got %f", test.p, test.expected, result)
+		}
+	}
+}
+
+func TestPercentileErrors(t *testing.T) {
+	if _, err := Percentile(enumerable.New([]int{1}), 101); !errors.Is(err, ErrInvalidQuantile) {
+		t.Errorf("Expected %v, got %v", ErrInvalidQuantile, err)
+	}
+	if _, err := Quantile(enumerable.New([]int{1}), -0.1); !errors.Is(err, ErrInvalidQuantile) {
+		t.Errorf("Expected %v, got %v", ErrInvalidQuantile, err)
+	}
+	if _, err := Median(enumerable.New([]int{})); !errors.Is(err, enumerable.ErrEmpty) {
+		t.Errorf("Expected %v, got %v", enumerable.ErrEmpty, err)
+	}
+}
+
+func TestQuantileNotFinite(t *testing.T) {
+	for _, x := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		e := enumerable.New([]float64{1, x, 3})
+		if _, err := Quantile(e, 0.5); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("Expected %v for %v, got %v", ErrNotFinite, x, err)
+		}
+		if _, err := Median(e); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("Expected %v for %v, got %v", ErrNotFinite, x, err)
+		}
+	}
+}
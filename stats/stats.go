@@ -0,0 +1,89 @@
+// Package stats provides descriptive statistics over numeric Enumerables
+package stats
+
+import (
+	"errors"
+	"math"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+var (
+	// ErrTooFewValues is returned when a sample statistic needs at least two values
+	ErrTooFewValues = errors.New("stats: at least two values required")
+	// ErrInvalidQuantile is returned when a quantile is outside of [0, 1] or a percentile outside of [0, 100]
+	ErrInvalidQuantile = errors.New("stats: quantile out of range")
+	// ErrNotFinite is returned by histograms and quantiles when a value is NaN or infinite, which cannot be ordered
+	// or binned
+	ErrNotFinite = errors.New("stats: value is NaN or infinite")
+)
+
+// Variance returns the population variance of the values in the Enumerable[T]
+// Computed in a single pass with Welford's algorithm
+// Returns enumerable.ErrEmpty if the Enumerable[T] is empty
+func Variance[T enumerable.Number](e enumerable.Enumerable[T]) (float64, error) {
+	w := enumerable.Fold(e, welford{}, addWelford[T])
+	if w.count == 0 {
+		return 0, enumerable.ErrEmpty
+	}
+	return w.m2 / float64(w.count), nil
+}
+
+// SampleVariance returns the sample variance of the values in the Enumerable[T] with Bessel's correction
+// Returns ErrTooFewValues if the Enumerable[T] has fewer than two values
+func SampleVariance[T enumerable.Number](e enumerable.Enumerable[T]) (float64, error) {
+	w := enumerable.Fold(e, welford{}, addWelford[T])
+	if w.count < 2 {
+		return 0, ErrTooFewValues
+	}
+	return w.m2 / float64(w.count-1), nil
+}
+
+// StdDev returns the population standard deviation of the values in the Enumerable[T]
+// Returns enumerable.ErrEmpty if the Enumerable[T] is empty
+func StdDev[T enumerable.Number](e enumerable.Enumerable[T]) (float64, error) {
+	v, err := Variance(e)
+	return math.Sqrt(v), err
+}
+
+// SampleStdDev returns the sample standard deviation of the values in the Enumerable[T]
+// Returns ErrTooFewValues if the Enumerable[T] has fewer than two values
+func SampleStdDev[T enumerable.Number](e enumerable.Enumerable[T]) (float64, error) {
+	v, err := SampleVariance(e)
+	return math.Sqrt(v), err
+}
+
+// Mode returns the most frequent value in the Enumerable[T]
+// Ties are broken by the value that reached the highest count first
+// Returns enumerable.ErrEmpty if the Enumerable[T] is empty
+func Mode[T enumerable.Number](e enumerable.Enumerable[T]) (T, error) {
+	counts := map[T]int{}
+	var mode T
+	best := 0
+	e.ForEach(func(v T) {
+		counts[v]++
+		if c := counts[v]; c > best {
+			mode, best = v, c
+		}
+	})
+	if best == 0 {
+		return mode, enumerable.ErrEmpty
+	}
+	return mode, nil
+}
+
+// welford is a running mean and sum of squared differences from the mean
+type welford struct {
+	count int
+	mean  float64
+	m2    float64
+}
+
+func addWelford[T enumerable.Number](w welford, v T) welford {
+	x := float64(v)
+	w.count++
+	delta := x - w.mean
+	w.mean += delta / float64(w.count)
+	w.m2 += delta * (x - w.mean)
+	return w
+}
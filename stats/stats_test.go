@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+func TestVariance(t *testing.T) {
+	e := enumerable.New([]int{2, 4, 4, 4, 5, 5, 7, 9})
+	result, err := Variance(e)
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if result != 4 {
+		t.Errorf("Expected %f, got %f", 4.0, result)
+	}
+}
+
+func TestVarianceEmpty(t *testing.T) {
+	e := enumerable.New([]int{})
+
+	if _, err := Variance(e); !errors.Is(err, enumerable.ErrEmpty) {
+		t.Errorf("Expected %v, got %v", enumerable.ErrEmpty, err)
+	}
+}
+
+func TestSampleVariance(t *testing.T) {
+	e := enumerable.New([]float64{1, 2, 3, 4})
+	result, _ := SampleVariance(e)
+	expected := 5.0 / 3
+
+	if math.Abs(result-expected) > 1e-12 {
+		t.Errorf("Expected %f, got %f", expected, result)
+	}
+	if _, err := SampleVariance(enumerable.New([]float64{1})); !errors.Is(err, ErrTooFewValues) {
+		t.Errorf("Expected %v, got %v", ErrTooFewValues, err)
+	}
+}
+
+func TestVarianceStable(t *testing.T) {
+	// a naive sum of squares loses all precision with a large offset
+	e := enumerable.New([]float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16})
+	result, _ := SampleVariance(e)
+
+	if math.Abs(result-30) > 1e-6 {
+		t.Errorf("Expected %f, got %f", 30.0, result)
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	e := enumerable.New([]int{2, 4, 4, 4, 5, 5, 7, 9})
+
+	if result, _ := StdDev(e); result != 2 {
+		t.Errorf("Expected %f, got %f", 2.0, result)
+	}
+	if result, _ := SampleStdDev(e); math.Abs(result-math.Sqrt(32.0/7)) > 1e-12 {
+		t.Errorf("Expected %f, got %f", math.Sqrt(32.0/7), result)
+	}
+}
+
+func TestMode(t *testing.T) {
+	e := enumerable.New([]int{1, 3, 2, 3, 1, 3})
+	result, err := Mode(e)
+
+	if err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+	if result != 3 {
+		t.Errorf("Expected %d, got %d", 3, result)
+	}
+	if _, err := Mode(enumerable.New([]int{})); !errors.Is(err, enumerable.ErrEmpty) {
+		t.Errorf("Expected %v, got %v", enumerable.ErrEmpty, err)
+	}
+}
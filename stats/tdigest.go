@@ -0,0 +1,158 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+// DefaultCompression is the compression used by ApproxQuantile
+// Higher values keep more centroids and give more accurate results
+const DefaultCompression = 100
+
+// TDigest is a mergeable sketch for estimating quantiles of a stream in bounded memory
+// Accuracy is highest near the tails of the distribution
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// NewTDigest creates an empty TDigest with the given compression
+// Uses DefaultCompression if compression is not positive
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add a value to the TDigest
+func (t *TDigest) Add(x float64) {
+	t.addCentroid(centroid{x, 1})
+}
+
+// Merge the values of another TDigest into this one
+func (t *TDigest) Merge(o *TDigest) {
+	o.compress()
+	for _, c := range o.centroids {
+		t.addCentroid(c)
+	}
+	t.min = math.Min(t.min, o.min)
+	t.max = math.Max(t.max, o.max)
+}
+
+// Count returns the number of values added to the TDigest
+func (t *TDigest) Count() int {
+	return int(t.count)
+}
+
+// Quantile returns the estimated q-th quantile where q is between 0 and 1
+// Returns ErrInvalidQuantile if q is out of range or enumerable.ErrEmpty if no values were added
+func (t *TDigest) Quantile(q float64) (float64, error) {
+	if q < 0 || q > 1 {
+		return 0, ErrInvalidQuantile
+	}
+	t.compress()
+	if len(t.centroids) == 0 {
+		return 0, enumerable.ErrEmpty
+	}
+	if q == 0 {
+		return t.min, nil
+	}
+	if q == 1 {
+		return t.max, nil
+	}
+	if len(t.centroids) == 1 {
+		return t.min + q*(t.max-t.min), nil
+	}
+
+	// interpolate between the centers of neighbouring centroids
+	target := q * t.count
+	first := t.centroids[0]
+	if target < first.weight/2 {
+		return t.min + (first.mean-t.min)*target/(first.weight/2), nil
+	}
+	cumulative := first.weight / 2
+	for i := 1; i < len(t.centroids); i++ {
+		previous, current := t.centroids[i-1], t.centroids[i]
+		step := (previous.weight + current.weight) / 2
+		if target < cumulative+step {
+			fraction := (target - cumulative) / step
+			return previous.mean + fraction*(current.mean-previous.mean), nil
+		}
+		cumulative += step
+	}
+	last := t.centroids[len(t.centroids)-1]
+	remaining := last.weight / 2
+	fraction := math.Min(1, (target-cumulative)/remaining)
+	return last.mean + fraction*(t.max-last.mean), nil
+}
+
+func (t *TDigest) addCentroid(c centroid) {
+	t.buffer = append(t.buffer, c)
+	t.count += c.weight
+	t.min = math.Min(t.min, c.mean)
+	t.max = math.Max(t.max, c.mean)
+	if len(t.buffer) >= int(t.compression)*5 {
+		t.compress()
+	}
+}
+
+// compress merges the buffered values into the centroids
+// Neighbouring centroids are combined while they span less than one unit of the scale function
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buffer...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := []centroid{all[0]}
+	before := 0.0
+	for _, c := range all[1:] {
+		current := &merged[len(merged)-1]
+		if t.scale((before+current.weight+c.weight)/t.count)-t.scale(before/t.count) <= 1 {
+			current.weight += c.weight
+			current.mean += (c.mean - current.mean) * c.weight / current.weight
+			continue
+		}
+		before += current.weight
+		merged = append(merged, c)
+	}
+	t.centroids = merged
+	t.buffer = t.buffer[:0]
+}
+
+// scale maps a quantile to a centroid index so centroids are smaller near the tails
+func (t *TDigest) scale(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*math.Min(q, 1)-1)
+}
+
+// ApproxQuantile estimates the q-th quantile of the Enumerable[T] in a single pass using a TDigest
+// Returns ErrInvalidQuantile if q is out of range or enumerable.ErrEmpty if the Enumerable[T] is empty
+func ApproxQuantile[T enumerable.Number](e enumerable.Enumerable[T], q float64) (float64, error) {
+	return Digest(e, DefaultCompression).Quantile(q)
+}
+
+// Digest builds a TDigest from the values of the Enumerable[T] in a single pass
+func Digest[T enumerable.Number](e enumerable.Enumerable[T], compression float64) *TDigest {
+	t := NewTDigest(compression)
+	e.ForEach(func(v T) {
+		t.Add(float64(v))
+	})
+	return t
+}
@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	enumerable "github.com/sdehm/go-enumerable"
+)
+
+func uniform(n int) enumerable.Enumerable[float64] {
+	values := make([]float64, n)
+	for i := range values {
+		// a permutation of 0..n-1 so insertion order is not sorted
+		values[i] = float64((i * 7919) % n)
+	}
+	return enumerable.New(values)
+}
+
+func TestApproxQuantile(t *testing.T) {
+	e := uniform(100000)
+	for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99} {
+		result, err := ApproxQuantile(e, q)
+		if err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		expected := q * 99999
+		if math.Abs(result-expected) > 100000*0.01 {
+			t.Errorf("Quantile %v: expected about %f, got %f", q, expected, result)
+		}
+	}
+}
+
+func TestTDigestBounds(t *testing.T) {
+	d := Digest(uniform(1000), 50)
+
+	if result, _ := d.Quantile(0); result != 0 {
+		t.Errorf("Expected %f, got %f", 0.0, result)
+	}
+	if result, _ := d.Quantile(1); result != 999 {
+		t.Errorf("Expected %f, got %f", 999.0, result)
+	}
+	if d.Count() != 1000 {
+		t.Errorf("Expected %d, got %d", 1000, d.Count())
+	}
+	if len(d.centroids) > 100 {
+		t.Errorf("Expected a bounded number of centroids, got %d", len(d.centroids))
+	}
+}
+
+func TestTDigestMerge(t *testing.T) {
+	a := NewTDigest(100)
+	b := NewTDigest(100)
+	for i := 0; i < 10000; i++ {
+		if i%2 == 0 {
+			a.Add(float64(i))
+		} else {
+			b.Add(float64(i))
+		}
+	}
+	a.Merge(b)
+	result, _ := a.Quantile(0.5)
+
+	if a.Count() != 10000 {
+		t.Errorf("Expected %d, got %d", 10000, a.Count())
+	}
+	if math.Abs(result-5000) > 100 {
+		t.Errorf("Expected about %f, got %f", 5000.0, result)
+	}
+}
+
+func TestTDigestSingleValue(t *testing.T) {
+	d := NewTDigest(0)
+	d.Add(3)
+
+	if result, _ := d.Quantile(0.5); result != 3 {
+		t.Errorf("Expected %f, got %f", 3.0, result)
+	}
+}
+
+func TestTDigestEmpty(t *testing.T) {
+	d := NewTDigest(100)
+
+	if _, err := d.Quantile(0.5); !errors.Is(err, enumerable.ErrEmpty) {
+		t.Errorf("Expected %v, got %v", enumerable.ErrEmpty, err)
+	}
+	if _, err := d.Quantile(2); !errors.Is(err, ErrInvalidQuantile) {
+		t.Errorf("Expected %v, got %v", ErrInvalidQuantile, err)
+	}
+}
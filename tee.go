@@ -0,0 +1,164 @@
+package enumerable
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Tee returns n Enumerables that share a single evaluation of the Enumerable[T], each returning all of its values
+// A consumer can read at most buffer values ahead of the slowest consumer that is still reading, and waits for it
+// to catch up otherwise, so with a positive buffer the Enumerables being evaluated at the same time must be evaluated
+// concurrently, such as one per goroutine, use Multicast to compute several results in a single pass on one goroutine
+// If buffer is not positive the values are buffered without limit
+// Consumers that have not been evaluated yet do not hold back the others, the values are kept for them until they
+// start reading, so the Enumerables can also be evaluated one after another
+// A consumer that stops early or finishes no longer holds back the others, the evaluation of the Enumerable[T]
+// stops early once every consumer has stopped
+// Each Enumerable can be evaluated once, evaluating it again returns ErrConsumed from ToListErr and ForEachErr
+// Panics if n is negative
+func (e Enumerable[T]) Tee(n int, buffer int) []Enumerable[T] {
+	if n < 0 {
+		panic("enumerable: tee count must not be negative")
+	}
+	t := &tee[T]{source: newPuller(e), buffer: buffer, positions: make([]int, n), started: make([]bool, n)}
+	t.wait = sync.NewCond(&t.mu)
+	// release the resources of a source that some consumers never finished once the tee is unreachable
+	runtime.AddCleanup(t, (*scope).release, t.source.scope)
+	result := make([]Enumerable[T], n)
+	for i := range result {
+		result[i] = fromSource(func(s *scope) iterator[T] {
+			if !t.start(i) {
+				s.fail(fmt.Errorf("%w: tee %d", ErrConsumed, i))
+				return emptyIterator[T]
+			}
+			s.onClose(func() { t.detach(i) })
+			return func() (T, bool) {
+				v, ok, err := t.at(i)
+				if err != nil {
+					s.fail(err)
+				}
+				return v, ok
+			}
+		})
+		result[i].observer = e.observer
+	}
+	return result
+}
+
+// detached is the position of a consumer that stopped reading
+const detached = -1
+
+// tee holds the values of an evaluation that have not been read by every consumer
+type tee[T any] struct {
+	mu        sync.Mutex
+	wait      *sync.Cond
+	source    *puller[T]
+	buffer    int
+	values    []T
+	base      int
+	positions []int
+	started   []bool
+	done      bool
+	err       error
+}
+
+// start marks consumer i as evaluated, returning false if it was evaluated before
+func (t *tee[T]) start(i int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started[i] {
+		return false
+	}
+	t.started[i] = true
+	return true
+}
+
+// at returns the next value of consumer i, waiting while it is buffer values ahead of the slowest consumer reading
+func (t *tee[T]) at(i int) (v T, ok bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for {
+		p := t.positions[i]
+		if p == detached {
+			return v, false, nil
+		}
+		if p < t.base+len(t.values) {
+			v = t.values[p-t.base]
+			t.positions[i]++
+			t.trim()
+			// wake the consumers waiting for this one, even if the values are kept for one that has not started
+			t.wait.Broadcast()
+			return v, true, nil
+		}
+		if t.done {
+			return v, false, t.err
+		}
+		if t.buffer > 0 && p-t.slowestReading() >= t.buffer {
+			t.wait.Wait()
+			continue
+		}
+		if v, ok, err := t.source.pull(); ok {
+			t.values = append(t.values, v)
+		} else {
+			t.finish(err)
+		}
+	}
+}
+
+// slowest returns the position of the slowest consumer that is still reading
+func (t *tee[T]) slowest() int {
+	slowest := t.base + len(t.values)
+	for _, p := range t.positions {
+		if p != detached {
+			slowest = min(slowest, p)
+		}
+	}
+	return slowest
+}
+
+// slowestReading returns the position of the slowest consumer that has started and is still reading
+// Waiting for a consumer that has not started would never end if it is evaluated after the waiting one
+func (t *tee[T]) slowestReading() int {
+	slowest := t.base + len(t.values)
+	for i, p := range t.positions {
+		if p != detached && t.started[i] {
+			slowest = min(slowest, p)
+		}
+	}
+	return slowest
+}
+
+// trim drops the values every consumer has read
+func (t *tee[T]) trim() {
+	if n := t.slowest() - t.base; n > 0 {
+		clear(t.values[:n])
+		t.values = t.values[n:]
+		t.base += n
+	}
+}
+
+// detach stops consumer i from holding back the others, stopping the evaluation once every consumer is detached
+func (t *tee[T]) detach(i int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.positions[i] = detached
+	t.trim()
+	t.wait.Broadcast()
+	for _, p := range t.positions {
+		if p != detached {
+			return
+		}
+	}
+	if !t.done {
+		t.source.release()
+		t.finish(nil)
+	}
+}
+
+// finish marks the evaluation as done and wakes the waiting consumers
+func (t *tee[T]) finish(err error) {
+	t.done = true
+	t.err = err
+	t.wait.Broadcast()
+}
@@ -0,0 +1,183 @@
+package enumerable
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counted returns a sequence of the values 0 to n - 1 counting its iterations and whether the last one stopped
+func counted(n int, iterations *atomic.Int64, stopped *atomic.Bool) Enumerable[int] {
+	return FromSeq(func(yield func(int) bool) {
+		iterations.Add(1)
+		defer stopped.Store(true)
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	})
+}
+
+func TestTee(t *testing.T) {
+	var iterations atomic.Int64
+	var stopped atomic.Bool
+	tees := counted(5, &iterations, &stopped).Map(func(i int) int { return i * 2 }).Tee(3, 0)
+	expected := []int{0, 2, 4, 6, 8}
+
+	for i, e := range tees {
+		if result := e.ToList(); !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected %v for tee %d, got %v", expected, i, result)
+		}
+	}
+	if iterations.Load() != 1 || !stopped.Load() {
+		t.Errorf("Expected one finished iteration, got %v", iterations.Load())
+	}
+}
+
+func TestTeeConcurrent(t *testing.T) {
+	var iterations atomic.Int64
+	var stopped atomic.Bool
+	tees := counted(1000, &iterations, &stopped).Tee(4, 8)
+	results := make([][]int, len(tees))
+
+	wg := sync.WaitGroup{}
+	for i, e := range tees {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = e.ToList()
+		}()
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if len(result) != 1000 || result[0] != 0 || result[999] != 999 {
+			t.Errorf("Expected 1000 values for tee %d, got %v", i, len(result))
+		}
+	}
+	if iterations.Load() != 1 {
+		t.Errorf("Expected %v iterations, got %v", 1, iterations.Load())
+	}
+}
+
+func TestTeeBuffer(t *testing.T) {
+	tees := New(make([]int, 20)).Map(func(i int) int { return i }).Tee(2, 4)
+	// the slow consumer reads one value and waits for release
+	started, release := make(chan struct{}), make(chan struct{})
+	slow := make(chan int)
+	go func() {
+		slow <- tees[1].Map(func(i int) int {
+			select {
+			case started <- struct{}{}:
+				<-release
+			default:
+			}
+			return i
+		}).Count()
+	}()
+	<-started
+
+	var read atomic.Int64
+	fast := make(chan []int)
+	go func() {
+		fast <- tees[0].Map(func(i int) int {
+			read.Add(1)
+			return i
+		}).ToList()
+	}()
+
+	for deadline := time.Now().Add(time.Second); read.Load() < 5 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if read.Load() != 5 {
+		t.Errorf("Expected the fast consumer to wait after %v values, got %v", 5, read.Load())
+	}
+	close(release)
+	if result := <-slow; result != 20 {
+		t.Errorf("Expected %v, got %v", 20, result)
+	}
+	if result := <-fast; len(result) != 20 {
+		t.Errorf("Expected %v, got %v", 20, len(result))
+	}
+}
+
+func TestTeeBufferSequential(t *testing.T) {
+	tees := New([]int{1, 2, 3, 4}).Tee(3, 1)
+	expected := []int{1, 2, 3, 4}
+
+	for i, e := range tees {
+		done := make(chan []int)
+		go func() { done <- e.ToList() }()
+		select {
+		case result := <-done:
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("Expected %v for tee %d, got %v", expected, i, result)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected tee %d not to wait for the tees that have not started", i)
+		}
+	}
+}
+
+func TestTeeStopsEarly(t *testing.T) {
+	var iterations atomic.Int64
+	var stopped atomic.Bool
+	tees := counted(100, &iterations, &stopped).Tee(2, 0)
+
+	if result := tees[0].Take(2).ToList(); !reflect.DeepEqual(result, []int{0, 1}) {
+		t.Errorf("Expected %v, got %v", []int{0, 1}, result)
+	}
+	if stopped.Load() {
+		t.Errorf("Expected the source to be read while a consumer has not stopped")
+	}
+	if result, ok := tees[1].ElementAt(3); !ok || result != 3 {
+		t.Errorf("Expected %v, got %v", 3, result)
+	}
+	if !stopped.Load() {
+		t.Errorf("Expected the source to stop once every consumer stopped")
+	}
+}
+
+func TestTeeConsumed(t *testing.T) {
+	tees := New([]int{1, 2}).Tee(1, 0)
+	tees[0].ToList()
+	_, err := tees[0].ToListErr()
+
+	if !errors.Is(err, ErrConsumed) {
+		t.Errorf("Expected %v, got %v", ErrConsumed, err)
+	}
+}
+
+func TestTeeError(t *testing.T) {
+	failed := errors.New("failed")
+	tees := FromSeqErr(func(yield func(int, error) bool) {
+		if yield(1, nil) {
+			yield(0, failed)
+		}
+	}).Tee(2, 0)
+
+	for _, e := range tees {
+		values := []int{}
+		err := e.ForEachErr(func(v int) { values = append(values, v) })
+		if !errors.Is(err, failed) {
+			t.Errorf("Expected %v, got %v", failed, err)
+		}
+		if !reflect.DeepEqual(values, []int{1}) {
+			t.Errorf("Expected %v, got %v", []int{1}, values)
+		}
+	}
+}
+
+func TestTeePanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected a panic for a negative count")
+		}
+	}()
+	New([]int{1}).Tee(-1, 0)
+}
@@ -0,0 +1,104 @@
+package enumerable
+
+import (
+	"cmp"
+	"container/heap"
+	"sort"
+)
+
+// TopK returns the k largest values of the Enumerable[T] by cmp, largest first
+// cmp returns a negative number when a is smaller than b, zero when equal and a positive number otherwise
+// Equal values keep their original order
+// Holds at most k values in memory while evaluating
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) TopK(k int, cmp func(a, b T) int) Enumerable[T] {
+	return e.stream(OpTopK, func(next iterator[T]) iterator[T] {
+		return deferred(func() []T {
+			h := topK[T]{k: k, cmp: cmp}
+			i := 0
+			for v, ok := next(); ok; v, ok = next() {
+				h.add(indexed[T]{i, v})
+				i++
+			}
+			return unwrapIndexed(h.sorted())
+		})
+	}).describe(func(s *stage[T]) { s.n, s.cmp = k, cmp })
+}
+
+// BottomK returns the k smallest values of the Enumerable[T] by cmp, smallest first
+// Equal values keep their original order
+// Holds at most k values in memory while evaluating
+// Evaluates lazily, call apply to evaluate
+func (e Enumerable[T]) BottomK(k int, cmp func(a, b T) int) Enumerable[T] {
+	return e.TopK(k, reverseCmp(cmp))
+}
+
+// TopKBy returns the k values of the Enumerable[T] with the largest keys, largest first
+// Equal keys keep their original order
+// Evaluates lazily, call apply to evaluate
+func TopKBy[T any, K cmp.Ordered](e Enumerable[T], k int, key func(T) K) Enumerable[T] {
+	return e.TopK(k, func(a, b T) int { return cmp.Compare(key(a), key(b)) })
+}
+
+// BottomKBy returns the k values of the Enumerable[T] with the smallest keys, smallest first
+// Equal keys keep their original order
+// Evaluates lazily, call apply to evaluate
+func BottomKBy[T any, K cmp.Ordered](e Enumerable[T], k int, key func(T) K) Enumerable[T] {
+	return e.BottomK(k, func(a, b T) int { return cmp.Compare(key(a), key(b)) })
+}
+
+func reverseCmp[T any](cmp func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int { return cmp(b, a) }
+}
+
+// topK is a min heap holding the best k values seen so far with the worst value at the root
+type topK[T any] struct {
+	k     int
+	cmp   func(a, b T) int
+	items []indexed[T]
+}
+
+// worse returns true if a ranks below b, later values rank below earlier equal values
+func (h *topK[T]) worse(a, b indexed[T]) bool {
+	if c := h.cmp(a.value, b.value); c != 0 {
+		return c < 0
+	}
+	return a.index > b.index
+}
+
+func (h *topK[T]) add(v indexed[T]) {
+	if h.k <= 0 {
+		return
+	}
+	if len(h.items) < h.k {
+		heap.Push(h, v)
+		return
+	}
+	if h.worse(h.items[0], v) {
+		h.items[0] = v
+		heap.Fix(h, 0)
+	}
+}
+
+func (h *topK[T]) merge(o topK[T]) {
+	for _, v := range o.items {
+		h.add(v)
+	}
+}
+
+// sorted returns the values best first
+func (h *topK[T]) sorted() []indexed[T] {
+	sort.Slice(h.items, func(i, j int) bool { return h.worse(h.items[j], h.items[i]) })
+	return h.items
+}
+
+func (h *topK[T]) Len() int           { return len(h.items) }
+func (h *topK[T]) Less(i, j int) bool { return h.worse(h.items[i], h.items[j]) }
+func (h *topK[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *topK[T]) Push(x any)         { h.items = append(h.items, x.(indexed[T])) }
+
+func (h *topK[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
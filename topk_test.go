@@ -0,0 +1,63 @@
+package enumerable
+
+import (
+	"cmp"
+	"reflect"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	e := New([]int{5, 1, 9, 3, 7, 9, 2})
+	result := e.TopK(3, cmp.Compare[int]).ToList()
+	expected := []int{9, 9, 7}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestBottomK(t *testing.T) {
+	e := New([]int{5, 1, 9, 3, 7, 9, 2})
+	result := e.BottomK(3, cmp.Compare[int]).ToList()
+	expected := []int{1, 2, 3}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestTopKEdges(t *testing.T) {
+	e := New([]int{2, 1, 3})
+
+	if result := e.TopK(0, cmp.Compare[int]).ToList(); len(result) != 0 {
+		t.Errorf("Expected 0 values, got %v", result)
+	}
+	if result := e.TopK(5, cmp.Compare[int]).ToList(); !reflect.DeepEqual(result, []int{3, 2, 1}) {
+		t.Errorf("Expected %v, got %v", []int{3, 2, 1}, result)
+	}
+	if result := New([]int{}).TopK(2, cmp.Compare[int]).ToList(); len(result) != 0 {
+		t.Errorf("Expected 0 values, got %v", result)
+	}
+}
+
+func TestTopKByStable(t *testing.T) {
+	e := New([]string{"bb", "a", "cc", "ddd", "ee"})
+	length := func(s string) int { return len(s) }
+
+	if result := TopKBy(e, 3, length).ToList(); !reflect.DeepEqual(result, []string{"ddd", "bb", "cc"}) {
+		t.Errorf("Expected %v, got %v", []string{"ddd", "bb", "cc"}, result)
+	}
+	if result := BottomKBy(e, 2, length).ToList(); !reflect.DeepEqual(result, []string{"a", "bb"}) {
+		t.Errorf("Expected %v, got %v", []string{"a", "bb"}, result)
+	}
+}
+
+func TestTopKAfterFilter(t *testing.T) {
+	e := New([]int{5, 1, 9, 3, 7, 9, 2}).Filter(func(i int) bool { return i < 9 })
+	result := e.TopK(2, cmp.Compare[int]).ToList()
+	expected := []int{7, 5}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,106 @@
+package enumerable
+
+import (
+	"context"
+	"sync"
+)
+
+// Attribute is a key and value attached to a Span, like attribute.KeyValue in OpenTelemetry
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is the part of an OpenTelemetry style span used by TraceObserver
+// An OpenTelemetry trace.Span is adapted by converting the attributes to attribute.KeyValue
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	AddEvent(name string, attributes ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans like an OpenTelemetry trace.Tracer
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TraceObserver returns an Observer that traces each operation and each parallel worker as a span started from ctx
+// Stage spans are named after the operation and have the attributes enumerable.stage, enumerable.evaluation and,
+// when they finish, enumerable.out, sampled values are added as element events with the attribute enumerable.index
+// Worker spans are named after the operation with a worker suffix, like MapParallel.worker
+func TraceObserver(ctx context.Context, tracer Tracer) Observer {
+	return &traceObserver{ctx: ctx, tracer: tracer, spans: map[spanKey]Span{}}
+}
+
+// spanKey identifies the span of a stage or a worker of an evaluation
+type spanKey struct {
+	evaluation uint64
+	index      int
+	worker     bool
+}
+
+type traceObserver struct {
+	ctx    context.Context
+	tracer Tracer
+	mu     sync.Mutex
+	spans  map[spanKey]Span
+}
+
+func (t *traceObserver) start(key spanKey, name string, attributes ...Attribute) {
+	_, span := t.tracer.Start(t.ctx, name)
+	span.SetAttributes(attributes...)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans[key] = span
+}
+
+func (t *traceObserver) span(key spanKey) Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.spans[key]
+}
+
+func (t *traceObserver) end(key spanKey, attributes ...Attribute) {
+	t.mu.Lock()
+	span := t.spans[key]
+	delete(t.spans, key)
+	t.mu.Unlock()
+	if span != nil {
+		span.SetAttributes(attributes...)
+		span.End()
+	}
+}
+
+func (t *traceObserver) StageStart(stage StageInfo) {
+	t.start(spanKey{stage.Evaluation, stage.Index, false}, string(stage.Op),
+		Attribute{"enumerable.stage", stage.Index},
+		Attribute{"enumerable.evaluation", stage.Evaluation})
+}
+
+func (t *traceObserver) Element(stage StageInfo, index int, value any) {
+	if span := t.span(spanKey{stage.Evaluation, stage.Index, false}); span != nil {
+		span.AddEvent("element", Attribute{"enumerable.index", index})
+	}
+}
+
+func (t *traceObserver) StageFinish(stage StageInfo, out int) {
+	t.end(spanKey{stage.Evaluation, stage.Index, false}, Attribute{"enumerable.out", out})
+}
+
+func (t *traceObserver) Error(stage StageInfo, err error) {
+	if span := t.span(spanKey{stage.Evaluation, stage.Index, false}); span != nil {
+		span.RecordError(err)
+	}
+}
+
+func (t *traceObserver) WorkerStart(worker WorkerInfo) {
+	t.start(spanKey{worker.Evaluation, worker.Worker, true}, string(worker.Op)+".worker",
+		Attribute{"enumerable.worker", worker.Worker},
+		Attribute{"enumerable.workers", worker.Workers},
+		Attribute{"enumerable.evaluation", worker.Evaluation})
+}
+
+func (t *traceObserver) WorkerFinish(worker WorkerInfo, items int) {
+	t.end(spanKey{worker.Evaluation, worker.Worker, true}, Attribute{"enumerable.items", items})
+}